@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// callbacker tells the caller how their copy went
+type callbacker interface {
+	callback(ctx context.Context, server string, req *pb.CallbackRequest) error
+}
+
+type prodCallbacker struct {
+	dial func(server string) (*grpc.ClientConn, error)
+}
+
+func (p *prodCallbacker) callback(ctx context.Context, server string, req *pb.CallbackRequest) error {
+	conn, err := p.dial(server)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = pb.NewFileCopierCallbackClient(conn).Callback(ctx, req)
+	return err
+}
+
+// finalStatus is the status of a copy which isn't going to be run again
+func finalStatus(err error, resp *pb.CopyResponse) pb.CopyStatus {
+	if err == nil && resp.GetStatus() == pb.CopyStatus_SKIPPED {
+		return pb.CopyStatus_SKIPPED
+	}
+
+	// Only a checksum mismatch comes back as DataLoss
+	switch status.Convert(err).Code() {
+	case codes.DataLoss:
+		return pb.CopyStatus_VERIFY_FAILED
+	case codes.Canceled:
+		return pb.CopyStatus_CANCELLED
+	}
+	if err != nil {
+		return pb.CopyStatus_FAILED
+	}
+	return pb.CopyStatus_COMPLETE
+}
+
+func millisBetween(start, end time.Time) int64 {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start).Milliseconds()
+}
+
+// callbackFor builds the callback for a finished entry, call with the queue lock held
+func callbackFor(entry *queueEntry) *pb.CallbackRequest {
+	started := entry.timeStarted
+	if started.IsZero() {
+		started = entry.timeFinished
+	}
+
+	return &pb.CallbackRequest{
+		Key:              entry.req.GetKey(),
+		Id:               entry.resp.GetId(),
+		Status:           entry.resp.GetStatus(),
+		Error:            entry.resp.GetError(),
+		ErrorCode:        entry.resp.GetErrorCode(),
+		BytesTransferred: entry.resp.GetBytesTransferred(),
+		MillisInQueue:    millisBetween(entry.timeAdded, started),
+		MillisToCopy:     millisBetween(entry.timeStarted, entry.timeFinished),
+	}
+}
+
+// sendCallback lets the caller know how the copy went, the callback goes into the
+// outbox first so that it's retried until it gets through
+func (s *Server) sendCallback(ctx context.Context, in *pb.CopyRequest, req *pb.CallbackRequest) {
+	if len(in.GetCallback()) == 0 {
+		return
+	}
+
+	d, err := s.outbox.add(in.GetCallback(), req)
+	if err != nil {
+		s.CtxLog(ctx, fmt.Sprintf("Unable to store callback to %v: %v", in.GetCallback(), err))
+	}
+	s.deliver(d)
+	s.outbox.wake()
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+)
+
+func waitForCallback(t *testing.T, s *Server) *pb.CallbackRequest {
+	for i := 0; i < 100; i++ {
+		if calls := s.callbacker.(*testCallbacker).received(); len(calls) > 0 {
+			return calls[0]
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+	t.Fatalf("No callback was made")
+	return nil
+}
+
+func TestCallbackOnSuccess(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL, Callback: "caller", Key: 20})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+
+	cb := waitForCallback(t, s)
+	if cb.GetKey() != 20 || cb.GetStatus() != pb.CopyStatus_COMPLETE || cb.GetBytesTransferred() != chunkSize*5/2 || cb.GetErrorCode() != 0 {
+		t.Errorf("Bad callback: %v", cb)
+	}
+}
+
+func TestCallbackOnFailure(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/madeup.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL, Callback: "caller", Key: 20})
+	if err == nil {
+		t.Fatalf("Copy did not fail")
+	}
+
+	cb := waitForCallback(t, s)
+	if cb.GetStatus() != pb.CopyStatus_FAILED || cb.GetErrorCode() != int32(codes.NotFound) || len(cb.GetError()) == 0 {
+		t.Errorf("Bad callback: %v", cb)
+	}
+}
+
+func TestCallbackOnQueuedFailure(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	go s.runQueue()
+
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/madeup.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL, Callback: "caller"})
+	waitForStatus(t, s, queued.GetId(), pb.CopyStatus_FAILED)
+
+	cb := waitForCallback(t, s)
+	if cb.GetStatus() != pb.CopyStatus_FAILED || cb.GetErrorCode() != int32(codes.NotFound) {
+		t.Errorf("Bad callback: %v", cb)
+	}
+
+	// A failed copy is tried again rather than standing in for the next one
+	resp, err := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/madeup.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL})
+	if err != nil || resp.GetId() == queued.GetId() {
+		t.Errorf("Failed copy was not requeued: %v, %v", resp, err)
+	}
+}
+
+func TestCallbackOnQueuedCancel(t *testing.T) {
+	s := InitTestServer()
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", Callback: "caller", Key: 20})
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: queued.GetId()})
+
+	cb := waitForCallback(t, s)
+	if cb.GetStatus() != pb.CopyStatus_CANCELLED || cb.GetId() != queued.GetId() || cb.GetMillisToCopy() != 0 {
+		t.Errorf("Bad callback: %v", cb)
+	}
+}
+
+func TestCallbackOnRunningCancel(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &blockingTransport{}
+	go s.runQueue()
+
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Callback: "caller"})
+	waitForStatus(t, s, queued.GetId(), pb.CopyStatus_IN_PROGRESS)
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: queued.GetId()})
+
+	cb := waitForCallback(t, s)
+	if cb.GetStatus() != pb.CopyStatus_CANCELLED || cb.GetErrorCode() != int32(codes.Canceled) {
+		t.Errorf("Bad callback: %v", cb)
+	}
+}
+
+func TestCallbackOnDeadLetter(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &testTransport{failWait: true}
+	go s.runQueue()
+
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Callback: "caller",
+		RetryPolicy: &pb.RetryPolicy{MaxAttempts: 2, InitialBackoffMs: 10}})
+
+	cb := waitForCallback(t, s)
+	if cb.GetStatus() != pb.CopyStatus_DEAD_LETTERED || cb.GetErrorCode() != int32(codes.Unavailable) {
+		t.Errorf("Bad callback: %v", cb)
+	}
+	if len(s.callbacker.(*testCallbacker).received()) != 1 {
+		t.Errorf("Retries were called back: %v", s.callbacker.(*testCallbacker).received())
+	}
+}
+
+func TestCallbackRetriedUntilDelivered(t *testing.T) {
+	s := InitTestServer()
+	s.callbacker = &testCallbacker{failures: 2}
+	s.outbox.backoff = &pb.RetryPolicy{InitialBackoffMs: 10, MaxBackoffMs: 10}
+	go s.runCallbacks()
+
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", Callback: "caller", Key: 20})
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: queued.GetId()})
+
+	cb := waitForCallback(t, s)
+	if cb.GetId() != queued.GetId() {
+		t.Errorf("Bad callback: %v", cb)
+	}
+
+	resp, err := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: queued.GetId()})
+	if err != nil {
+		t.Fatalf("Unable to get status: %v", err)
+	}
+	d := resp.GetEntry().GetCallback()
+	if d.GetState() != pb.CallbackState_CALLBACK_DELIVERED || d.GetAttempts() != 3 || len(d.GetLastError()) != 0 || d.GetDeliveredTime() == 0 {
+		t.Errorf("Bad delivery state: %v", d)
+	}
+}
+
+func TestCallbackOutboxSurvivesRestart(t *testing.T) {
+	s := InitTestServer()
+	s.callbacker = &testCallbacker{failures: 1}
+	s.outbox.backoff = &pb.RetryPolicy{InitialBackoffMs: 10, MaxBackoffMs: 10}
+
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", Callback: "caller"})
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: queued.GetId()})
+
+	resp, _ := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: queued.GetId()})
+	d := resp.GetEntry().GetCallback()
+	if d.GetState() != pb.CallbackState_CALLBACK_PENDING || d.GetAttempts() != 1 || len(d.GetLastError()) == 0 || d.GetNextAttemptTime() == 0 {
+		t.Errorf("Bad delivery state: %v", d)
+	}
+
+	s = restartServer(t, s)
+	time.Sleep(time.Millisecond * 50)
+	s.deliverCallbacks()
+
+	cb := waitForCallback(t, s)
+	if cb.GetId() != queued.GetId() || cb.GetStatus() != pb.CopyStatus_CANCELLED {
+		t.Errorf("Bad callback: %v", cb)
+	}
+	resp, _ = s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: queued.GetId()})
+	if resp.GetEntry().GetCallback().GetState() != pb.CallbackState_CALLBACK_DELIVERED {
+		t.Errorf("Callback was not delivered: %v", resp)
+	}
+}
+
+func TestCallbackWithoutCopyId(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.callbacker = &testCallbacker{failures: 1}
+
+	s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL, Callback: "caller"})
+	if len(s.outbox.deliveries) != 1 || len(s.outbox.deliveries[0].GetRequest().GetId()) == 0 {
+		t.Errorf("Callback was not given an id: %v", s.outbox.deliveries)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// blockingTransport runs copies which never finish until they're cancelled
+type blockingTransport struct{}
+
+type blockingTransfer struct {
+	ctx context.Context
+}
+
+func (b *blockingTransport) start(ctx context.Context, in *pb.CopyRequest) (transfer, error) {
+	ioutil.WriteFile(in.GetOutputFile(), []byte("partial"), 0644)
+	return &blockingTransfer{ctx: ctx}, nil
+}
+
+func (b *blockingTransport) classify(err error, output string) codes.Code {
+	return classifyFile(err)
+}
+
+func (b *blockingTransport) sshKeys() bool {
+	return false
+}
+
+func (b *blockingTransfer) progress() (int64, int64) {
+	return 0, 0
+}
+
+func (b *blockingTransfer) wait() (string, error) {
+	<-b.ctx.Done()
+	return "", b.ctx.Err()
+}
+
+func waitForStatus(t *testing.T, s *Server, id string, expected pb.CopyStatus) {
+	for i := 0; i < 100; i++ {
+		resp, err := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: id})
+		if err == nil && resp.GetEntry().GetResp().GetStatus() == expected {
+			return
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+	t.Fatalf("Copy %v never reached %v", id, expected)
+}
+
+func TestCancelQueued(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	ioutil.WriteFile(tempPath(dir+"/out.txt"), []byte("partial"), 0644)
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+
+	resp, err := s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: queued.GetId()})
+	if err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if resp.GetEntry().GetResp().GetStatus() != pb.CopyStatus_CANCELLED || s.scheduler.len() != 0 {
+		t.Errorf("Copy was not cancelled: %v", resp)
+	}
+	if _, err := os.Stat(tempPath(dir + "/out.txt")); !os.IsNotExist(err) {
+		t.Errorf("Partial output was left behind: %v", err)
+	}
+
+	_, err = s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: queued.GetId()})
+	if status.Convert(err).Code() != codes.FailedPrecondition {
+		t.Errorf("Cancelled twice: %v", err)
+	}
+}
+
+func TestCancelRunning(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &blockingTransport{}
+	go s.runQueue()
+
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Key: 12})
+	waitForStatus(t, s, queued.GetId(), pb.CopyStatus_IN_PROGRESS)
+
+	_, err := s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Key: 12})
+	if err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	waitForStatus(t, s, queued.GetId(), pb.CopyStatus_CANCELLED)
+
+	if _, err := os.Stat(tempPath(dir + "/out.txt")); !os.IsNotExist(err) {
+		t.Errorf("Partial output was left behind: %v", err)
+	}
+	if _, err := os.Stat(dir + "/out.txt"); !os.IsNotExist(err) {
+		t.Errorf("Cancelled copy was moved into place: %v", err)
+	}
+}
+
+func TestPauseQueue(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &blockingTransport{}
+	s.PauseQueue(context.Background(), &pb.PauseQueueRequest{})
+	go s.runQueue()
+
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+	time.Sleep(time.Millisecond * 100)
+
+	list, _ := s.ListQueue(context.Background(), &pb.ListQueueRequest{})
+	if !list.GetPaused() || list.GetEntries()[0].GetResp().GetStatus() != pb.CopyStatus_IN_QUEUE {
+		t.Errorf("Queue was not paused: %v", list)
+	}
+
+	s.ResumeQueue(context.Background(), &pb.ResumeQueueRequest{})
+	waitForStatus(t, s, queued.GetId(), pb.CopyStatus_IN_PROGRESS)
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: queued.GetId()})
+	waitForStatus(t, s, queued.GetId(), pb.CopyStatus_CANCELLED)
+}
+
+func TestCancelCommand(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	running, err := startCommand(ctx, "sleep", "30")
+	if err != nil {
+		t.Fatalf("Unable to start: %v", err)
+	}
+
+	start := time.Now()
+	cancel()
+	_, err = running.wait()
+	if err == nil || time.Now().Sub(start) > commandGrace {
+		t.Errorf("Command was not stopped: %v after %v", err, time.Now().Sub(start))
+	}
+	if classifyCopy(ctx, &scpTransport{}, err, "") != codes.Canceled {
+		t.Errorf("Cancel was not reported: %v", classifyCopy(ctx, &scpTransport{}, err, ""))
+	}
+}
+
+func TestCancelLocalCopy(t *testing.T) {
+	_, dir := InitStreamTestServer(t)
+	src, _ := os.Open(dir + "/in.txt")
+	defer src.Close()
+	info, _ := src.Stat()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var copied int64
+	err := copyLocal(ctx, src, info, dir+"/out.txt", &copied)
+	if err == nil || copied != 0 {
+		t.Errorf("Cancelled copy ran: %v, %v", err, copied)
+	}
+}
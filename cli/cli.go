@@ -2,8 +2,12 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/brotherlogic/goserver/utils"
 	"google.golang.org/grpc"
@@ -38,8 +42,82 @@ func main() {
 		for _, server := range resp.Server {
 			fmt.Printf("Accepts: '%v'\n", server)
 		}
+	} else if os.Args[1] == "status" {
+		key, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err != nil {
+			log.Fatalf("Bad key %v: %v", os.Args[2], err)
+		}
+		resp, err := client.GetCopyStatus(ctx, &pb.CopyStatusRequest{Key: key})
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		if job := resp.GetJob(); job != nil {
+			fmt.Printf("%v: %v/%v files copied, %v failed (%v)\n", job.GetStatus(), job.GetFilesComplete(), job.GetFilesTotal(), job.GetFilesFailed(), job.GetError())
+		} else {
+			fmt.Printf("%v\n", resp.GetEntry())
+		}
+	} else if os.Args[1] == "watch" {
+		key, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err != nil {
+			log.Fatalf("Bad key %v: %v", os.Args[2], err)
+		}
+		wctx, wcancel := utils.ManualContext("filecopier-cli", time.Hour*24)
+		defer wcancel()
+		stream, err := client.WatchCopy(wctx, &pb.WatchCopyRequest{Key: key})
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		for {
+			event, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Fatalf("Error: %v", err)
+			}
+			fmt.Printf("%v %v/%v bytes (%.0f B/s, eta %v)\n", event.GetStatus(), event.GetBytesDone(), event.GetBytesTotal(),
+				event.GetBytesPerSecond(), time.Duration(event.GetEtaMillis())*time.Millisecond)
+		}
+	} else if os.Args[1] == "queue" {
+		req := &pb.ListQueueRequest{}
+		for _, st := range os.Args[2:] {
+			req.Status = append(req.Status, pb.CopyStatus(pb.CopyStatus_value[strings.ToUpper(st)]))
+		}
+		resp, err := client.ListQueue(ctx, req)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		for _, entry := range resp.GetEntries() {
+			fmt.Printf("%v [%v] %v:%v -> %v:%v (%v)\n", entry.GetResp().GetIndexInQueue(), entry.GetResp().GetStatus(),
+				entry.GetReq().GetInputServer(), entry.GetReq().GetInputFile(), entry.GetReq().GetOutputServer(), entry.GetReq().GetOutputFile(), entry.GetResp().GetError())
+		}
+	} else if os.Args[1] == "deadletters" {
+		resp, err := client.ListDeadLetters(ctx, &pb.ListDeadLettersRequest{})
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		for _, entry := range resp.GetEntries() {
+			fmt.Printf("%v %v:%v -> %v:%v (%v)\n", entry.GetResp().GetId(), entry.GetReq().GetInputServer(), entry.GetReq().GetInputFile(),
+				entry.GetReq().GetOutputServer(), entry.GetReq().GetOutputFile(), entry.GetResp().GetError())
+		}
+	} else if os.Args[1] == "requeue" {
+		resp, err := client.RequeueDeadLetter(ctx, &pb.RequeueDeadLetterRequest{Id: os.Args[2]})
+		fmt.Printf("%v and %v\n", resp, err)
+	} else if os.Args[1] == "purge" {
+		req := &pb.PurgeDeadLettersRequest{Ids: os.Args[2:]}
+		if len(os.Args) == 3 && os.Args[2] == "all" {
+			req = &pb.PurgeDeadLettersRequest{All: true}
+		}
+		if len(req.GetIds()) == 0 && !req.GetAll() {
+			log.Fatalf("Give the ids of the dead letters to purge, or all")
+		}
+		resp, err := client.PurgeDeadLetters(ctx, req)
+		fmt.Printf("%v and %v\n", resp, err)
 	} else {
 		q := &pb.CopyRequest{InputFile: os.Args[1], InputServer: os.Args[2], OutputFile: os.Args[3], OutputServer: os.Args[4]}
+		if len(os.Args) > 5 {
+			q.Transport = pb.TransportType(pb.TransportType_value[strings.ToUpper(os.Args[5])])
+		}
 		resp, err := client.QueueCopy(ctx, q)
 
 		fmt.Printf("%v -> %v and %v\n", q, resp, err)
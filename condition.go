@@ -0,0 +1,57 @@
+package main
+
+import (
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/status"
+)
+
+// seconds drops what scp doesn't keep of a modification time
+func seconds(t int64) int64 {
+	return t / 1e9
+}
+
+// skipCopy checks a copy's condition against what's at the destination, it's
+// true if there's no need for the copy
+func (s *Server) skipCopy(ctx context.Context, in *pb.CopyRequest) (bool, error) {
+	dest, err := s.fs.stat(ctx, in.GetOutputServer(), in.GetOutputFile())
+	if err != nil {
+		return false, status.Errorf(status.Convert(err).Code(), "Unable to check %v: %v", in.GetOutputFile(), err)
+	}
+	if !dest.GetExists() || dest.GetIsDir() {
+		return false, nil
+	}
+	if in.GetCondition() == pb.CopyCondition_IF_MISSING {
+		return true, nil
+	}
+
+	source, err := s.fs.stat(ctx, in.GetInputServer(), in.GetInputFile())
+	if err != nil {
+		return false, status.Errorf(status.Convert(err).Code(), "Unable to check %v: %v", in.GetInputFile(), err)
+	}
+	// The copy itself reports a missing source
+	if !source.GetExists() {
+		return false, nil
+	}
+
+	switch in.GetCondition() {
+	case pb.CopyCondition_IF_NEWER:
+		return seconds(source.GetModTime()) <= seconds(dest.GetModTime()), nil
+	case pb.CopyCondition_IF_SIZE_OR_MTIME_DIFFERS:
+		return source.GetSize() == dest.GetSize() && seconds(source.GetModTime()) == seconds(dest.GetModTime()), nil
+	case pb.CopyCondition_IF_HASH_DIFFERS:
+		if source.GetSize() != dest.GetSize() {
+			return false, nil
+		}
+		sourceSum, err := s.fs.checksum(ctx, in.GetInputServer(), in.GetInputFile(), in.GetChecksumAlgorithm())
+		if err != nil {
+			return false, status.Errorf(status.Convert(err).Code(), "Unable to checksum %v: %v", in.GetInputFile(), err)
+		}
+		destSum, err := s.fs.checksum(ctx, in.GetOutputServer(), in.GetOutputFile(), in.GetChecksumAlgorithm())
+		if err != nil {
+			return false, status.Errorf(status.Convert(err).Code(), "Unable to checksum %v: %v", in.GetOutputFile(), err)
+		}
+		return sourceSum.GetChecksum() == destSum.GetChecksum(), nil
+	}
+	return false, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+)
+
+func TestCopyConditions(t *testing.T) {
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		condition pb.CopyCondition
+		dest      string
+		destTime  time.Time
+		skipped   bool
+	}{
+		{pb.CopyCondition_ALWAYS_COPY, "source", old, false},
+		{pb.CopyCondition_IF_MISSING, "", old, false},
+		{pb.CopyCondition_IF_MISSING, "other", old, true},
+		{pb.CopyCondition_IF_NEWER, "other", recent, true},
+		{pb.CopyCondition_IF_NEWER, "other", old.Add(-time.Hour), false},
+		{pb.CopyCondition_IF_SIZE_OR_MTIME_DIFFERS, "SOURCE", old.Add(time.Millisecond * 500), true},
+		{pb.CopyCondition_IF_SIZE_OR_MTIME_DIFFERS, "SOURCE", recent, false},
+		{pb.CopyCondition_IF_SIZE_OR_MTIME_DIFFERS, "longer source", old, false},
+		{pb.CopyCondition_IF_HASH_DIFFERS, "source", recent, true},
+		{pb.CopyCondition_IF_HASH_DIFFERS, "SOURCE", old, false},
+	}
+
+	for _, test := range tests {
+		s := InitTestServer()
+		dir := t.TempDir()
+		ioutil.WriteFile(dir+"/in.txt", []byte("source"), 0644)
+		os.Chtimes(dir+"/in.txt", old, old)
+		if len(test.dest) > 0 {
+			ioutil.WriteFile(dir+"/out.txt", []byte(test.dest), 0644)
+			os.Chtimes(dir+"/out.txt", test.destTime, test.destTime)
+		}
+
+		resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL, Condition: test.condition})
+		if err != nil {
+			t.Fatalf("Copy %v failed: %v", test.condition, err)
+		}
+
+		data, _ := ioutil.ReadFile(dir + "/out.txt")
+		if test.skipped && (resp.GetStatus() != pb.CopyStatus_SKIPPED || string(data) != test.dest) {
+			t.Errorf("%v over %q was not skipped: %v, %q", test.condition, test.dest, resp, data)
+		}
+		if !test.skipped && (resp.GetStatus() == pb.CopyStatus_SKIPPED || string(data) != "source") {
+			t.Errorf("%v over %q was not copied: %v, %q", test.condition, test.dest, resp, data)
+		}
+	}
+}
+
+func TestQueuedCopySkipped(t *testing.T) {
+	s := InitTestServer()
+	go s.runQueue()
+	dir := t.TempDir()
+	ioutil.WriteFile(dir+"/in.txt", []byte("source"), 0644)
+	ioutil.WriteFile(dir+"/out.txt", []byte("other"), 0644)
+
+	resp, err := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Condition: pb.CopyCondition_IF_MISSING, Callback: "caller"})
+	if err != nil {
+		t.Fatalf("Queue failed: %v", err)
+	}
+	waitForStatus(t, s, resp.GetId(), pb.CopyStatus_SKIPPED)
+
+	if cb := waitForCallback(t, s); cb.GetStatus() != pb.CopyStatus_SKIPPED {
+		t.Errorf("Bad callback: %v", cb)
+	}
+	if data, _ := ioutil.ReadFile(dir + "/out.txt"); string(data) != "other" {
+		t.Errorf("Skipped copy was copied: %q", data)
+	}
+}
+
+func TestDirCopySkipsUnchanged(t *testing.T) {
+	s := InitTestServer()
+	runJobs(s)
+	in, out := makeTree(t, "a.txt", "b.txt")
+	writeFiles(out, "a.txt")
+
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, Condition: pb.CopyCondition_IF_MISSING})
+	if err != nil {
+		t.Fatalf("DirCopy failed: %v", err)
+	}
+	job := waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+	if job.GetFilesComplete() != 1 || job.GetFilesSkipped() != 1 {
+		t.Errorf("Bad job: %v", job)
+	}
+	if data, _ := ioutil.ReadFile(out + "/a.txt"); string(data) != "old a.txt" {
+		t.Errorf("Existing file was copied over: %q", data)
+	}
+}
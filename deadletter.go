@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// deadLetter moves an entry which has run out of retries out of the queue, call with the queue lock held
+func (s *Server) deadLetter(ctx context.Context, entry *queueEntry) {
+	entry.resp.Status = pb.CopyStatus_DEAD_LETTERED
+	entry.resp.NextAttemptTime = 0
+	entry.timeFinished = time.Now()
+
+	s.unpersist(ctx, entry)
+	var nq []*queueEntry
+	for _, q := range s.queue {
+		if q != entry {
+			nq = append(nq, q)
+		}
+	}
+	s.queue = nq
+
+	s.deadLetters = append(s.deadLetters, entry)
+	if err := s.deadLetterLog.append(entry.toProto()); err != nil {
+		s.CtxLog(ctx, fmt.Sprintf("Unable to store dead letter %v: %v", entry.resp.GetId(), err))
+	}
+	s.notify(entry)
+	s.RaiseIssue("Copy gave up", fmt.Sprintf("Copy of %v from %v to %v failed %v times: %v",
+		entry.req.GetInputFile(), entry.req.GetInputServer(), entry.req.GetOutputServer(), entry.resp.GetRepeats(), entry.resp.GetError()))
+}
+
+// ListDeadLetters lists the copies which ran out of retries
+func (s *Server) ListDeadLetters(ctx context.Context, req *pb.ListDeadLettersRequest) (*pb.ListDeadLettersResponse, error) {
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
+	resp := &pb.ListDeadLettersResponse{}
+	for _, d := range s.deadLetters {
+		resp.Entries = append(resp.Entries, proto.Clone(d.toProto()).(*pb.QueueEntry))
+	}
+	return resp, nil
+}
+
+// RequeueDeadLetter puts a dead letter back in the queue with a fresh set of retries
+func (s *Server) RequeueDeadLetter(ctx context.Context, req *pb.RequeueDeadLetterRequest) (*pb.RequeueDeadLetterResponse, error) {
+	if s.scheduler.len() > 20 {
+		return nil, status.Errorf(codes.ResourceExhausted, "Queue is full")
+	}
+
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
+	var entry *queueEntry
+	var remaining []*queueEntry
+	for _, d := range s.deadLetters {
+		if d.resp.GetId() == req.GetId() {
+			entry = d
+		} else {
+			remaining = append(remaining, d)
+		}
+	}
+	if entry == nil {
+		return nil, status.Errorf(codes.NotFound, "No dead letter with id %v", req.GetId())
+	}
+
+	// The file may have been queued again since it was given up on
+	for _, q := range s.queue {
+		if sameCopy(q.req, entry.req) && !finished(q.resp.GetStatus()) {
+			return nil, status.Errorf(codes.AlreadyExists, "%v is already being copied as %v", entry.req.GetInputFile(), q.resp.GetId())
+		}
+	}
+	var nq []*queueEntry
+	for _, q := range s.queue {
+		if sameCopy(q.req, entry.req) {
+			s.unpersist(ctx, q)
+		} else {
+			nq = append(nq, q)
+		}
+	}
+	s.queue = nq
+
+	s.deadLetters = remaining
+	if err := s.deadLetterLog.append(&pb.QueueEntry{Resp: &pb.CopyResponse{Id: entry.resp.GetId()}, Removed: true}); err != nil {
+		s.CtxLog(ctx, fmt.Sprintf("Unable to remove dead letter %v: %v", entry.resp.GetId(), err))
+	}
+
+	entry.resp.Status = pb.CopyStatus_IN_QUEUE
+	entry.resp.Repeats = 0
+	entry.resp.Error = ""
+	entry.resp.ErrorCode = 0
+	entry.timeAdded = time.Now()
+	entry.timeStarted = time.Time{}
+	entry.timeFinished = time.Time{}
+	s.queue = append(s.queue, entry)
+	s.persist(ctx, entry)
+	s.scheduler.push(entry, entry.timeAdded)
+
+	entry.resp.IndexInQueue = s.indexInQueue(entry)
+	return &pb.RequeueDeadLetterResponse{Response: proto.Clone(entry.resp).(*pb.CopyResponse)}, nil
+}
+
+// PurgeDeadLetters throws away dead letters along with anything they left half copied
+func (s *Server) PurgeDeadLetters(ctx context.Context, req *pb.PurgeDeadLettersRequest) (*pb.PurgeDeadLettersResponse, error) {
+	ids := make(map[string]bool)
+	for _, id := range req.GetIds() {
+		ids[id] = true
+	}
+
+	s.queueMutex.Lock()
+	var purged, remaining []*queueEntry
+	var entries []*pb.QueueEntry
+	for _, d := range s.deadLetters {
+		if req.GetAll() || ids[d.resp.GetId()] {
+			purged = append(purged, d)
+		} else {
+			remaining = append(remaining, d)
+			entries = append(entries, d.toProto())
+		}
+	}
+	s.deadLetters = remaining
+	err := s.deadLetterLog.rewrite(entries)
+	s.queueMutex.Unlock()
+
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Unable to purge dead letters: %v", err)
+	}
+
+	for _, d := range purged {
+		tin := proto.Clone(d.req).(*pb.CopyRequest)
+		tin.OutputFile = tempPath(d.req.GetOutputFile())
+		s.abandon(ctx, tin, status.Errorf(codes.Aborted, "Purged"))
+	}
+	return &pb.PurgeDeadLettersResponse{Purged: int32(len(purged))}, nil
+}
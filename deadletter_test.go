@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetriesEndInDeadLetter(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &testTransport{failWait: true}
+	go s.runQueue()
+
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt",
+		RetryPolicy: &pb.RetryPolicy{MaxAttempts: 2, InitialBackoffMs: 10}})
+	waitForStatus(t, s, queued.GetId(), pb.CopyStatus_DEAD_LETTERED)
+
+	dead, _ := s.ListDeadLetters(context.Background(), &pb.ListDeadLettersRequest{})
+	if len(dead.GetEntries()) != 1 || dead.GetEntries()[0].GetResp().GetRepeats() != 2 || dead.GetEntries()[0].GetResp().GetErrorCode() != int32(codes.Unavailable) {
+		t.Errorf("Bad dead letters: %v", dead)
+	}
+
+	list, _ := s.ListQueue(context.Background(), &pb.ListQueueRequest{})
+	if len(list.GetEntries()) != 0 {
+		t.Errorf("Dead letter is still queued: %v", list)
+	}
+}
+
+// deadLetterCopy queues a copy and marks it as having given up
+func deadLetterCopy(s *Server, in *pb.CopyRequest) string {
+	resp, _ := s.QueueCopy(context.Background(), in)
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
+	entry := s.findEntry(resp.GetId(), 0)
+	s.scheduler.remove(entry)
+	entry.resp.Repeats = 10
+	entry.resp.Error = "Gave up"
+	s.deadLetter(context.Background(), entry)
+	return resp.GetId()
+}
+
+func TestRequeueDeadLetter(t *testing.T) {
+	s := InitTestServer()
+	id := deadLetterCopy(s, &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+
+	// Dead letters survive a restart
+	s = restartServer(t, s)
+	resp, err := s.RequeueDeadLetter(context.Background(), &pb.RequeueDeadLetterRequest{Id: id})
+	if err != nil {
+		t.Fatalf("Requeue failed: %v", err)
+	}
+	if resp.GetResponse().GetStatus() != pb.CopyStatus_IN_QUEUE || resp.GetResponse().GetRepeats() != 0 || resp.GetResponse().GetId() != id {
+		t.Errorf("Bad requeue: %v", resp)
+	}
+	if s.scheduler.len() != 1 {
+		t.Errorf("Requeued copy was not scheduled")
+	}
+
+	s = restartServer(t, s)
+	dead, _ := s.ListDeadLetters(context.Background(), &pb.ListDeadLettersRequest{})
+	if len(dead.GetEntries()) != 0 || len(s.queue) != 1 {
+		t.Errorf("Requeue was not persisted: %v, %v", dead, s.queue)
+	}
+
+	_, err = s.RequeueDeadLetter(context.Background(), &pb.RequeueDeadLetterRequest{Id: id})
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Requeued twice: %v", err)
+	}
+}
+
+func TestRequeueDeadLetterQueued(t *testing.T) {
+	s := InitTestServer()
+	id := deadLetterCopy(s, &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+
+	_, err := s.RequeueDeadLetter(context.Background(), &pb.RequeueDeadLetterRequest{Id: id})
+	if status.Convert(err).Code() != codes.AlreadyExists {
+		t.Errorf("Requeued over a queued copy: %v", err)
+	}
+	dead, _ := s.ListDeadLetters(context.Background(), &pb.ListDeadLettersRequest{})
+	if len(dead.GetEntries()) != 1 || len(s.queue) != 1 {
+		t.Errorf("Failed requeue changed things: %v, %v", dead, s.queue)
+	}
+}
+
+func TestRequeueDeadLetterQueueFull(t *testing.T) {
+	s := InitTestServer()
+	id := deadLetterCopy(s, &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+	for i := 0; i < 21; i++ {
+		s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: fmt.Sprintf("in%v", i), OutputFile: "out"})
+	}
+
+	_, err := s.RequeueDeadLetter(context.Background(), &pb.RequeueDeadLetterRequest{Id: id})
+	if status.Convert(err).Code() != codes.ResourceExhausted {
+		t.Errorf("Requeued onto a full queue: %v", err)
+	}
+}
+
+func TestPurgeDeadLetters(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	ioutil.WriteFile(tempPath(dir+"/out1.txt"), []byte("partial"), 0644)
+	first := deadLetterCopy(s, &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out1.txt"})
+	deadLetterCopy(s, &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out2.txt"})
+	deadLetterCopy(s, &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out3.txt"})
+
+	resp, err := s.PurgeDeadLetters(context.Background(), &pb.PurgeDeadLettersRequest{Ids: []string{first}})
+	if err != nil || resp.GetPurged() != 1 {
+		t.Fatalf("Bad purge: %v, %v", resp, err)
+	}
+	if _, err := os.Stat(tempPath(dir + "/out1.txt")); !os.IsNotExist(err) {
+		t.Errorf("Partial copy was not removed: %v", err)
+	}
+
+	s = restartServer(t, s)
+	resp, _ = s.PurgeDeadLetters(context.Background(), &pb.PurgeDeadLettersRequest{})
+	if resp.GetPurged() != 0 {
+		t.Errorf("Purge without ids purged something: %v", resp)
+	}
+	resp, _ = s.PurgeDeadLetters(context.Background(), &pb.PurgeDeadLettersRequest{All: true})
+	if resp.GetPurged() != 2 {
+		t.Errorf("Bad purge of everything: %v", resp)
+	}
+	dead, _ := s.ListDeadLetters(context.Background(), &pb.ListDeadLettersRequest{})
+	if len(dead.GetEntries()) != 0 {
+		t.Errorf("Dead letters were not purged: %v", dead)
+	}
+}
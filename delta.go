@@ -0,0 +1,528 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"github.com/cespare/xxhash/v2"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	minBlockSize = 2 * 1024
+	maxBlockSize = 128 * 1024
+
+	// maxBlocks keeps a signature to around a megabyte, it goes in a single
+	// message and gRPC won't take one over 4MB
+	maxBlocks = 64 * 1024
+)
+
+// deltaBlockSize picks a block size for a file, bigger files get bigger
+// blocks to keep their signatures down to a sensible size
+func deltaBlockSize(size int64) int32 {
+	bs := int64(math.Sqrt(float64(size))) &^ 1023
+	if bs < minBlockSize {
+		bs = minBlockSize
+	}
+	if bs > maxBlockSize {
+		bs = maxBlockSize
+	}
+
+	// Past that the blocks grow with the file so there's never too many of them
+	if bs*maxBlocks < size {
+		bs = ((size+maxBlocks-1)/maxBlocks + 1023) &^ 1023
+	}
+	return int32(bs)
+}
+
+// rolling is the rsync weak checksum, which can slide along a byte at a time
+type rolling struct {
+	a, b uint32
+	size uint32
+}
+
+func newRolling(block []byte) *rolling {
+	r := &rolling{size: uint32(len(block))}
+	for i, c := range block {
+		r.a += uint32(c)
+		r.b += uint32(len(block)-i) * uint32(c)
+	}
+	return r
+}
+
+func (r *rolling) roll(out, in byte) {
+	r.a += uint32(in) - uint32(out)
+	r.b += r.a - r.size*uint32(out)
+}
+
+func (r *rolling) sum() uint32 {
+	return r.a&0xffff | r.b<<16
+}
+
+func strongSum(block []byte) []byte {
+	sum := make([]byte, 8)
+	binary.BigEndian.PutUint64(sum, xxhash.Sum64(block))
+	return sum
+}
+
+// signatureFile describes the blocks of a file, a file that isn't there has no blocks
+func signatureFile(path string, blockSize int32) (*pb.Signature, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &pb.Signature{BlockSize: minBlockSize}, nil
+	}
+	if err != nil {
+		return nil, status.Errorf(classifyFile(err), "Unable to read %v: %v", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, status.Errorf(classifyFile(err), "Unable to read %v: %v", path, err)
+	}
+	if blockSize <= 0 || int64(blockSize)*maxBlocks < info.Size() {
+		blockSize = deltaBlockSize(info.Size())
+	}
+
+	sig := &pb.Signature{BlockSize: blockSize}
+	r := bufio.NewReader(f)
+	block := make([]byte, blockSize)
+	for {
+		n, err := io.ReadFull(r, block)
+		if n > 0 {
+			sig.Size += int64(n)
+			sig.Blocks = append(sig.Blocks, &pb.BlockSignature{Weak: newRolling(block[:n]).sum(), Strong: strongSum(block[:n])})
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return sig, nil
+		}
+		if err != nil {
+			return nil, status.Errorf(classifyFile(err), "Unable to read %v: %v", path, err)
+		}
+	}
+}
+
+// deltaWriter batches up the ops of a delta, joining runs of blocks together
+type deltaWriter struct {
+	emit    func(op *pb.DeltaOp) error
+	run     *pb.DeltaOp
+	literal []byte
+}
+
+func (d *deltaWriter) flush() error {
+	if d.run != nil {
+		if err := d.emit(d.run); err != nil {
+			return err
+		}
+		d.run = nil
+	}
+	if len(d.literal) > 0 {
+		if err := d.emit(&pb.DeltaOp{Data: d.literal}); err != nil {
+			return err
+		}
+		d.literal = nil
+	}
+	return nil
+}
+
+func (d *deltaWriter) block(i int64) error {
+	if d.run != nil && d.run.GetBlock()+d.run.GetBlocks() == i {
+		d.run.Blocks++
+		return nil
+	}
+	if err := d.flush(); err != nil {
+		return err
+	}
+	d.run = &pb.DeltaOp{Block: i, Blocks: 1}
+	return nil
+}
+
+func (d *deltaWriter) data(b ...byte) error {
+	if d.run != nil {
+		if err := d.flush(); err != nil {
+			return err
+		}
+	}
+	d.literal = append(d.literal, b...)
+	if len(d.literal) >= chunkSize {
+		return d.flush()
+	}
+	return nil
+}
+
+// computeDelta works out the ops that rebuild a file from a basis with the given signature
+func computeDelta(src *os.File, basis *pb.Signature, emit func(op *pb.DeltaOp) error) error {
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	bs := int(basis.GetBlockSize())
+	if bs <= 0 {
+		bs = minBlockSize
+	}
+	if err := emit(&pb.DeltaOp{Size: info.Size(), BlockSize: int32(bs), Mode: uint32(info.Mode().Perm()), ModTime: info.ModTime().UnixNano()}); err != nil {
+		return err
+	}
+
+	// Only full blocks can be matched, the last one in the basis may be short
+	index := make(map[uint32][]int64)
+	for i, b := range basis.GetBlocks() {
+		if int64(i+1)*int64(bs) <= basis.GetSize() {
+			index[b.GetWeak()] = append(index[b.GetWeak()], int64(i))
+		}
+	}
+	match := func(r *rolling, window []byte) (int64, bool) {
+		candidates := index[r.sum()]
+		if len(candidates) == 0 {
+			return 0, false
+		}
+		strong := strongSum(window)
+		for _, i := range candidates {
+			if string(basis.GetBlocks()[i].GetStrong()) == string(strong) {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	d := &deltaWriter{emit: emit}
+	buf := make([]byte, 0, 4*bs+chunkSize)
+	start := 0
+	eof := false
+	fill := func() error {
+		// The window is all we need to keep, what's behind it is already in the delta
+		buf = append(buf[:0], buf[start:]...)
+		start = 0
+		for len(buf) < cap(buf) && !eof {
+			n, err := src.Read(buf[len(buf):cap(buf)])
+			buf = buf[:len(buf)+n]
+			if err == io.EOF {
+				eof = true
+			} else if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var r *rolling
+	for {
+		// Sliding the window along needs the byte after it
+		if len(buf)-start <= bs && !eof {
+			if err := fill(); err != nil {
+				return err
+			}
+		}
+		if len(buf)-start < bs {
+			break
+		}
+
+		if r == nil {
+			r = newRolling(buf[start : start+bs])
+		}
+		if i, ok := match(r, buf[start:start+bs]); ok {
+			if err := d.block(i); err != nil {
+				return err
+			}
+			start += bs
+			r = nil
+			continue
+		}
+
+		if err := d.data(buf[start]); err != nil {
+			return err
+		}
+		if len(buf)-start > bs {
+			r.roll(buf[start], buf[start+bs])
+		} else {
+			r = nil
+		}
+		start++
+	}
+
+	if err := d.data(buf[start:]...); err != nil {
+		return err
+	}
+	return d.flush()
+}
+
+// deltaSink takes the ops of a delta and rebuilds the file
+type deltaSink interface {
+	send(op *pb.DeltaOp) error
+	close() (*pb.ApplyDeltaResponse, error)
+	abort()
+}
+
+// fileDelta rebuilds a file on this server from a basis alongside it
+type fileDelta struct {
+	basisPath string
+	path      string
+	basis     *os.File
+	out       *os.File
+	blockSize int64
+	mode      os.FileMode
+	modTime   time.Time
+	resp      *pb.ApplyDeltaResponse
+}
+
+func (f *fileDelta) send(op *pb.DeltaOp) error {
+	if f.out == nil {
+		basis, err := os.Open(f.basisPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		f.basis = basis
+		f.out, err = os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		f.blockSize = int64(op.GetBlockSize())
+		f.mode = os.FileMode(op.GetMode())
+		f.modTime = fromUnixNano(op.GetModTime())
+		f.resp = &pb.ApplyDeltaResponse{}
+	}
+
+	if op.GetBlocks() > 0 {
+		if f.basis == nil {
+			return status.Errorf(codes.FailedPrecondition, "There's no %v to take blocks from", f.basisPath)
+		}
+		n, err := io.Copy(f.out, io.NewSectionReader(f.basis, op.GetBlock()*f.blockSize, op.GetBlocks()*f.blockSize))
+		if err != nil {
+			return err
+		}
+		if n != op.GetBlocks()*f.blockSize {
+			return status.Errorf(codes.Aborted, "%v changed while it was being used as a basis", f.basisPath)
+		}
+		f.resp.Reused += n
+		f.resp.Size += n
+	}
+
+	n, err := f.out.Write(op.GetData())
+	f.resp.Size += int64(n)
+	return err
+}
+
+func (f *fileDelta) close() (*pb.ApplyDeltaResponse, error) {
+	if f.basis != nil {
+		f.basis.Close()
+	}
+	if f.out == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "No delta was received for %v", f.path)
+	}
+	if err := f.out.Close(); err != nil {
+		return nil, err
+	}
+	if f.mode != 0 {
+		if err := os.Chmod(f.path, f.mode); err != nil {
+			return nil, err
+		}
+	}
+	if !f.modTime.IsZero() {
+		if err := os.Chtimes(f.path, f.modTime, f.modTime); err != nil {
+			return nil, err
+		}
+	}
+	return f.resp, nil
+}
+
+func (f *fileDelta) abort() {
+	if f.basis != nil {
+		f.basis.Close()
+	}
+	if f.out != nil {
+		f.out.Close()
+	}
+}
+
+type applyDeltaSink struct {
+	stream pb.FileCopierService_ApplyDeltaClient
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+}
+
+func (a *applyDeltaSink) send(op *pb.DeltaOp) error {
+	err := a.stream.Send(op)
+	if err == io.EOF {
+		// The receiver has bailed, the real error comes back from the close
+		_, err = a.stream.CloseAndRecv()
+	}
+	return err
+}
+
+func (a *applyDeltaSink) close() (*pb.ApplyDeltaResponse, error) {
+	defer a.conn.Close()
+	defer a.cancel()
+	return a.stream.CloseAndRecv()
+}
+
+func (a *applyDeltaSink) abort() {
+	a.cancel()
+	a.conn.Close()
+}
+
+// deltaTransport sends only what's changed from the file already at the destination
+type deltaTransport struct {
+	dial    func(ctx context.Context, job, server string) (*grpc.ClientConn, error)
+	isLocal func(server string) bool
+}
+
+type deltaTransfer struct {
+	copied int64
+	total  int64
+	saved  int64
+	done   chan error
+}
+
+// savingTransfer is a transfer which can avoid sending everything
+type savingTransfer interface {
+	bytesSaved() int64
+}
+
+func (d *deltaTransport) signature(ctx context.Context, server, path string) (*pb.Signature, error) {
+	if d.isLocal(server) {
+		return signatureFile(path, 0)
+	}
+
+	conn, err := d.dial(ctx, "filecopier", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return pb.NewFileCopierServiceClient(conn).Signature(ctx, &pb.SignatureRequest{Path: path})
+}
+
+// produce runs the delta at the source, handing each op over as it's worked out
+func (d *deltaTransport) produce(ctx context.Context, server, path string, basis *pb.Signature, emit func(op *pb.DeltaOp) error) error {
+	if d.isLocal(server) {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return computeDelta(f, basis, emit)
+	}
+
+	conn, err := d.dial(ctx, "filecopier", server)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stream, err := pb.NewFileCopierServiceClient(conn).Delta(ctx, &pb.DeltaRequest{Path: path, Basis: basis})
+	if err != nil {
+		return err
+	}
+	for {
+		op, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := emit(op); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *deltaTransport) openSink(ctx context.Context, server, basis, path string) (deltaSink, error) {
+	if d.isLocal(server) {
+		return &fileDelta{basisPath: basis, path: path}, nil
+	}
+
+	conn, err := d.dial(ctx, "filecopier", server)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := pb.NewFileCopierServiceClient(conn).ApplyDelta(ctx)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
+	}
+	return &applyDeltaSink{stream: stream, conn: conn, cancel: cancel}, nil
+}
+
+func (d *deltaTransport) start(ctx context.Context, in *pb.CopyRequest) (transfer, error) {
+	// We're writing to a temp file, the basis is the file it's going to replace
+	basis := outputPath(in.GetOutputFile())
+	sig, err := d.signature(ctx, in.GetOutputServer(), basis)
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := d.openSink(ctx, in.GetOutputServer(), basis, in.GetOutputFile())
+	if err != nil {
+		return nil, err
+	}
+
+	t := &deltaTransfer{done: make(chan error, 1)}
+	go func() {
+		t.done <- t.pump(ctx, d, in, sig, sink)
+	}()
+	return t, nil
+}
+
+func (d *deltaTransport) classify(err error, output string) codes.Code {
+	return classifyFile(err)
+}
+
+func (d *deltaTransport) sshKeys() bool {
+	return false
+}
+
+func (t *deltaTransfer) pump(ctx context.Context, d *deltaTransport, in *pb.CopyRequest, sig *pb.Signature, sink deltaSink) error {
+	first := true
+	var blockSize int64
+	err := d.produce(ctx, in.GetInputServer(), in.GetInputFile(), sig, func(op *pb.DeltaOp) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if first {
+			atomic.StoreInt64(&t.total, op.GetSize())
+			blockSize = int64(op.GetBlockSize())
+			op.BasisPath = outputPath(in.GetOutputFile())
+			op.Path = in.GetOutputFile()
+			first = false
+		}
+		if err := sink.send(op); err != nil {
+			return err
+		}
+		atomic.AddInt64(&t.copied, int64(len(op.GetData()))+op.GetBlocks()*blockSize)
+		return nil
+	})
+	if err != nil {
+		sink.abort()
+		return err
+	}
+
+	resp, err := sink.close()
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt64(&t.copied, resp.GetSize())
+	atomic.StoreInt64(&t.saved, resp.GetReused())
+	return nil
+}
+
+func (t *deltaTransfer) progress() (int64, int64) {
+	return atomic.LoadInt64(&t.copied), atomic.LoadInt64(&t.total)
+}
+
+func (t *deltaTransfer) wait() (string, error) {
+	return "", <-t.done
+}
+
+func (t *deltaTransfer) bytesSaved() int64 {
+	return atomic.LoadInt64(&t.saved)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// makeDeltaFiles leaves an old version of a file at the output, and a new one
+// with some of it changed, some inserted and some taken out at the input
+func makeDeltaFiles(t *testing.T) string {
+	dir := t.TempDir()
+	old := make([]byte, 1024*1024)
+	rand.New(rand.NewSource(1)).Read(old)
+
+	updated := append([]byte{}, old[:100000]...)
+	updated = append(updated, []byte("inserted")...)
+	updated = append(updated, old[100000:500000]...)
+	updated = append(updated, bytes.Repeat([]byte("x"), 5000)...)
+	updated = append(updated, old[505000:900000]...)
+	updated = append(updated, old[910000:]...)
+
+	ioutil.WriteFile(dir+"/in.txt", updated, 0640)
+	ioutil.WriteFile(dir+"/out.txt", old, 0644)
+	return dir
+}
+
+func checkDelta(t *testing.T, dir string, resp *pb.CopyResponse) {
+	in, _ := ioutil.ReadFile(dir + "/in.txt")
+	out, _ := ioutil.ReadFile(dir + "/out.txt")
+	if !bytes.Equal(in, out) {
+		t.Errorf("Files differ: %v vs %v", len(in), len(out))
+	}
+	if resp.GetBytesSaved() < int64(len(in))*9/10 {
+		t.Errorf("Delta saved too little: %v of %v", resp.GetBytesSaved(), len(in))
+	}
+	if info, _ := os.Stat(dir + "/out.txt"); info.Mode().Perm() != 0640 {
+		t.Errorf("Mode was not kept: %v", info.Mode())
+	}
+}
+
+func TestDeltaCopy(t *testing.T) {
+	s := InitTestServer()
+	dir := makeDeltaFiles(t)
+
+	resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", TransferMode: pb.TransferMode_DELTA_TRANSFER})
+	if err != nil {
+		t.Fatalf("Delta copy failed: %v", err)
+	}
+	checkDelta(t, dir, resp)
+}
+
+func TestDeltaCopyBetweenServers(t *testing.T) {
+	for _, remote := range []string{"input", "output"} {
+		s := InitTestServer()
+		s.Registry.Identifier = "local"
+		s.delta = &deltaTransport{dial: serveTestServer(t, s), isLocal: s.isLocal}
+		dir := makeDeltaFiles(t)
+
+		in := &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", TransferMode: pb.TransferMode_DELTA_TRANSFER}
+		if remote == "input" {
+			in.InputServer = "remote"
+		} else {
+			in.OutputServer = "remote"
+		}
+		resp, err := s.Copy(context.Background(), in)
+		if err != nil {
+			t.Fatalf("Delta copy from %v failed: %v", remote, err)
+		}
+		checkDelta(t, dir, resp)
+	}
+}
+
+func TestDeltaCopyWithoutBasis(t *testing.T) {
+	s := InitTestServer()
+	dir := makeDeltaFiles(t)
+	os.Remove(dir + "/out.txt")
+
+	resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", TransferMode: pb.TransferMode_DELTA_TRANSFER})
+	if err != nil {
+		t.Fatalf("Delta copy failed: %v", err)
+	}
+	in, _ := ioutil.ReadFile(dir + "/in.txt")
+	out, _ := ioutil.ReadFile(dir + "/out.txt")
+	if !bytes.Equal(in, out) || resp.GetBytesSaved() != 0 || resp.GetBytesTransferred() != int64(len(in)) {
+		t.Errorf("Bad copy: %v vs %v, %v", len(in), len(out), resp)
+	}
+}
+
+func TestRollingChecksum(t *testing.T) {
+	data := make([]byte, 10000)
+	rand.New(rand.NewSource(2)).Read(data)
+
+	r := newRolling(data[:4096])
+	for i := 0; i+4096 < len(data); i++ {
+		r.roll(data[i], data[i+4096])
+		if r.sum() != newRolling(data[i+1:i+4097]).sum() {
+			t.Fatalf("Rolled checksum differs at %v", i+1)
+		}
+	}
+}
+
+func TestSignatureFitsInAMessage(t *testing.T) {
+	// gRPC's default limit on a received message
+	limit := 4 * 1024 * 1024
+	for _, size := range []int64{1024 * 1024, 32 << 30, 50 << 30, 1 << 40} {
+		bs := deltaBlockSize(size)
+		sig := &pb.Signature{BlockSize: bs, Size: size}
+		for i := int64(0); i < (size+int64(bs)-1)/int64(bs); i++ {
+			sig.Blocks = append(sig.Blocks, &pb.BlockSignature{Weak: math.MaxUint32, Strong: bytes.Repeat([]byte{0xff}, 8)})
+		}
+
+		req := &pb.DeltaRequest{Path: "/media/disk/some/long/path/to/a/large/file.img", Basis: sig}
+		if proto.Size(req) >= limit {
+			t.Errorf("Signature of a %v byte file is %v bytes with %v blocks of %v", size, proto.Size(req), len(sig.Blocks), bs)
+		}
+	}
+}
+
+func TestSignatureFileLimitsBlocks(t *testing.T) {
+	dir := t.TempDir()
+	ioutil.WriteFile(dir+"/in.txt", make([]byte, maxBlocks*3), 0644)
+
+	sig, err := signatureFile(dir+"/in.txt", 1)
+	if err != nil || len(sig.GetBlocks()) > maxBlocks || sig.GetBlockSize() != deltaBlockSize(maxBlocks*3) {
+		t.Errorf("Bad signature: %v blocks of %v, %v", len(sig.GetBlocks()), sig.GetBlockSize(), err)
+	}
+
+	sig, err = signatureFile(dir+"/in.txt", 4096)
+	if err != nil || sig.GetBlockSize() != 4096 {
+		t.Errorf("Block size was not kept: %v, %v", sig.GetBlockSize(), err)
+	}
+}
+
+func TestDeltaBasisChanged(t *testing.T) {
+	dir := t.TempDir()
+	ioutil.WriteFile(dir+"/basis.txt", make([]byte, 1000), 0644)
+	f := &fileDelta{basisPath: dir + "/basis.txt", path: dir + "/out.txt"}
+	defer f.abort()
+
+	if err := f.send(&pb.DeltaOp{Size: 2048, BlockSize: 2048}); err != nil {
+		t.Fatalf("First op failed: %v", err)
+	}
+	err := f.send(&pb.DeltaOp{Block: 0, Blocks: 1})
+	if status.Convert(err).Code() != codes.Aborted || finalStatus(err, &pb.CopyResponse{}) != pb.CopyStatus_FAILED {
+		t.Errorf("Bad error for a changed basis: %v", err)
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"github.com/brotherlogic/goserver/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// jobFeedLimit is how full directory copies will fill the queue, leaving
+	// room for copies queued on their own
+	jobFeedLimit = 10
+
+	// jobRetention is how long we remember finished directory copies
+	jobRetention = time.Hour * 24
+
+	// jobPriority is the priority of files in a directory copy which doesn't set one
+	jobPriority = 100
+
+	// jobInterval is how often directory copies are checked on if nothing wakes them
+	jobInterval = time.Second * 5
+)
+
+// dirJobs holds the directory copies on disk while they run
+type dirJobs struct {
+	file     string
+	mutex    sync.Mutex
+	jobs     []*pb.DirJob
+	interval time.Duration
+	wakeup   chan struct{}
+}
+
+func newDirJobs(file string) *dirJobs {
+	return &dirJobs{file: file, interval: jobInterval, wakeup: make(chan struct{}, 1)}
+}
+
+func (d *dirJobs) load() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	data, err := ioutil.ReadFile(d.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	list := &pb.DirJobs{}
+	if err := proto.Unmarshal(data, list); err != nil {
+		return err
+	}
+	d.jobs = list.GetJobs()
+	return nil
+}
+
+// save writes out the jobs, dropping anything which finished long enough ago, call with the lock held
+func (d *dirJobs) save() error {
+	var jobs []*pb.DirJob
+	for _, j := range d.jobs {
+		if j.GetTimeFinished() == 0 || time.Since(fromUnixNano(j.GetTimeFinished())) < jobRetention {
+			jobs = append(jobs, j)
+		}
+	}
+	d.jobs = jobs
+
+	data, err := proto.Marshal(&pb.DirJobs{Jobs: d.jobs})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(d.file, data)
+}
+
+// put adds or replaces a job
+func (d *dirJobs) put(job *pb.DirJob) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	job = proto.Clone(job).(*pb.DirJob)
+	for i, j := range d.jobs {
+		if j.GetId() == job.GetId() {
+			d.jobs[i] = job
+			return d.save()
+		}
+	}
+	d.jobs = append(d.jobs, job)
+	return d.save()
+}
+
+// find looks up a job by id, or the latest with the key if there's no id
+func (d *dirJobs) find(id string, key int64) *pb.DirJob {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for i := len(d.jobs) - 1; i >= 0; i-- {
+		j := d.jobs[i]
+		if (len(id) > 0 && j.GetId() == id) || (len(id) == 0 && j.GetReq().GetKey() == key) {
+			return proto.Clone(j).(*pb.DirJob)
+		}
+	}
+	return nil
+}
+
+// running lists the jobs which haven't finished
+func (d *dirJobs) running() []*pb.DirJob {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var jobs []*pb.DirJob
+	for _, j := range d.jobs {
+		if !finished(j.GetStatus()) {
+			jobs = append(jobs, proto.Clone(j).(*pb.DirJob))
+		}
+	}
+	return jobs
+}
+
+func (d *dirJobs) wake() {
+	select {
+	case d.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// DirCopy copies everything under a directory, returning the id of the job doing the copying
+func (s *Server) DirCopy(ctx context.Context, in *pb.CopyRequest) (*pb.CopyResponse, error) {
+	if len(in.GetInputFile()) == 0 || len(in.GetOutputFile()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "You need to supply an input and an output directory")
+	}
+	if err := validatePolicy(in.GetRetryPolicy()); err != nil {
+		return nil, err
+	}
+	if err := validateMetadata(in.GetMetadata()); err != nil {
+		return nil, err
+	}
+
+	if _, err := newFilter(in.GetInclude(), in.GetExclude()); err != nil {
+		return nil, err
+	}
+	if in.GetMirror() && len(in.GetTrashDir()) > 0 && within(in.GetOutputFile(), in.GetTrashDir()) {
+		return nil, status.Errorf(codes.InvalidArgument, "The trash directory %v can't be inside the mirror %v", in.GetTrashDir(), in.GetOutputFile())
+	}
+
+	list, err := s.fs.list(ctx, in.GetInputServer(), &pb.ListDirRequest{
+		Path:              in.GetInputFile(),
+		Include:           in.GetInclude(),
+		Exclude:           in.GetExclude(),
+		Symlinks:          in.GetSymlinks(),
+		SpecialFiles:      in.GetSpecialFiles(),
+		PreserveHardlinks: in.GetPreserveHardlinks(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	job := &pb.DirJob{Id: newID(), Req: in, Status: pb.CopyStatus_IN_QUEUE, TimeAdded: time.Now().UnixNano(), FilesSkipped: list.GetSkipped()}
+	for _, e := range list.GetEntries() {
+		switch {
+		case e.GetIsDir():
+			job.Dirs = append(job.Dirs, e)
+		case len(e.GetSymlink()) > 0 || len(e.GetHardlink()) > 0:
+			job.Links = append(job.Links, e)
+		default:
+			job.Pending = append(job.Pending, e)
+		}
+	}
+	job.FilesTotal = int32(len(job.Pending) + len(job.Links))
+
+	var dest []*pb.FileEntry
+	if in.GetMirror() || in.GetDryRun() {
+		dest, err = s.listDestination(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deleting := 0
+	if in.GetMirror() {
+		planMirror(job, dest)
+		for _, e := range job.GetExtraneous() {
+			if !e.GetIsDir() {
+				deleting++
+			}
+		}
+	}
+
+	resp := &pb.CopyResponse{
+		Id:           job.GetId(),
+		Status:       job.GetStatus(),
+		TimeInQueue:  job.GetTimeAdded(),
+		FilesSkipped: job.GetFilesSkipped(),
+		FilesAdded:   job.GetFilesAdded(),
+		FilesUpdated: job.GetFilesUpdated(),
+		FilesDeleted: int32(deleting),
+	}
+	if in.GetDryRun() {
+		resp.Id = ""
+		resp.Status = pb.CopyStatus_UNKNOWN
+		resp.Plan = s.planDirCopy(ctx, job, dest)
+		return resp, nil
+	}
+
+	if err := s.jobs.put(job); err != nil {
+		return nil, status.Errorf(codes.Internal, "Unable to store directory copy: %v", err)
+	}
+	s.jobs.wake()
+
+	s.CtxLog(ctx, fmt.Sprintf("Copying %v directories, %v files and %v links from %v, skipping %v", len(job.Dirs), len(job.Pending), len(job.Links), in.GetInputFile(), job.GetFilesSkipped()))
+	return resp, nil
+}
+
+// childRequest is the copy of a single file in a directory copy
+func childRequest(in *pb.CopyRequest, file *pb.FileEntry) *pb.CopyRequest {
+	child := proto.Clone(in).(*pb.CopyRequest)
+	child.InputFile = filepath.Join(in.GetInputFile(), file.GetPath())
+	child.OutputFile = filepath.Join(in.GetOutputFile(), file.GetPath())
+
+	// The job reports on the copy as a whole
+	child.Key = 0
+	child.Callback = ""
+	child.Include = nil
+	child.Exclude = nil
+	child.Mirror = false
+	child.TrashDir = ""
+	child.DryRun = false
+	child.PreserveHardlinks = false
+	// A copy of the same file done earlier says nothing about what's there now
+	child.Override = true
+	if child.GetPriority() == 0 {
+		child.Priority = jobPriority
+	}
+	return child
+}
+
+// advanceJob moves a directory copy along as far as it can go for now
+func (s *Server) advanceJob(ctx context.Context, job *pb.DirJob) {
+	in := job.GetReq()
+
+	// Clear out the destination of a mirror first, so nothing is in the way
+	// of what's coming, then everything needs somewhere to go before it can be copied
+	if s.removeExtraneous(ctx, job) {
+		for len(job.Dirs) > 0 {
+			d := job.Dirs[0]
+			err := s.fs.makeDir(ctx, in.GetOutputServer(), filepath.Join(in.GetOutputFile(), d.GetPath()), d.GetMode())
+			if err != nil {
+				job.Error = fmt.Sprintf("%v", err)
+				if status.Convert(err).Code() != codes.Unavailable {
+					job.FilesFailed += int32(len(job.Pending))
+					job.Pending = nil
+					job.Dirs = nil
+				}
+				break
+			}
+			job.Dirs = job.Dirs[1:]
+		}
+	}
+
+	s.queueMutex.Lock()
+	var active []string
+	for _, id := range job.Active {
+		entry := s.findEntry(id, 0)
+		switch {
+		case entry == nil:
+			job.FilesFailed++
+			job.Error = fmt.Sprintf("Copy %v was lost", id)
+		case entry.resp.GetStatus() == pb.CopyStatus_COMPLETE:
+			job.FilesComplete++
+			job.BytesTransferred += entry.resp.GetBytesTransferred()
+		case entry.resp.GetStatus() == pb.CopyStatus_SKIPPED:
+			job.FilesSkipped++
+		case finished(entry.resp.GetStatus()):
+			job.FilesFailed++
+			job.Error = entry.resp.GetError()
+		default:
+			active = append(active, id)
+		}
+	}
+	s.queueMutex.Unlock()
+	job.Active = active
+
+	for len(job.Extraneous) == 0 && len(job.Dirs) == 0 && len(job.Pending) > 0 && s.scheduler.len() < jobFeedLimit {
+		resp, err := s.enqueue(ctx, childRequest(in, job.Pending[0]))
+		if err != nil {
+			job.FilesFailed++
+			job.Error = fmt.Sprintf("%v", err)
+		} else {
+			job.Active = append(job.Active, resp.GetId())
+		}
+		job.Pending = job.Pending[1:]
+	}
+
+	// Links go in last, once whatever they point to is there
+	if len(job.Extraneous) == 0 && len(job.Dirs) == 0 && len(job.Pending) == 0 && len(job.Active) == 0 {
+		s.makeLinks(ctx, job)
+	}
+
+	switch {
+	case len(job.Extraneous) > 0 || len(job.Dirs) > 0 || len(job.Pending) > 0 || len(job.Active) > 0 || len(job.Links) > 0:
+		if job.FilesComplete+job.FilesFailed+job.FilesDeleted+int32(len(job.Active)) > 0 {
+			job.Status = pb.CopyStatus_IN_PROGRESS
+		}
+	case job.FilesFailed > 0:
+		job.Status = pb.CopyStatus_FAILED
+	default:
+		job.Status = pb.CopyStatus_COMPLETE
+	}
+
+	if finished(job.GetStatus()) {
+		job.TimeFinished = time.Now().UnixNano()
+	}
+	if err := s.jobs.put(job); err != nil {
+		s.CtxLog(ctx, fmt.Sprintf("Unable to store directory copy %v: %v", job.GetId(), err))
+	}
+
+	if finished(job.GetStatus()) {
+		s.sendCallback(ctx, in, &pb.CallbackRequest{
+			Key:              in.GetKey(),
+			Id:               job.GetId(),
+			Status:           job.GetStatus(),
+			Error:            job.GetError(),
+			BytesTransferred: job.GetBytesTransferred(),
+			MillisToCopy:     millisBetween(fromUnixNano(job.GetTimeAdded()), fromUnixNano(job.GetTimeFinished())),
+		})
+	}
+}
+
+// runJobs keeps the directory copies moving, feeding their files into the queue as it has room
+func (s *Server) runJobs() {
+	for {
+		ctx, cancel := utils.ManualContext("fc-jobs", time.Minute*10)
+		for _, job := range s.jobs.running() {
+			s.advanceJob(ctx, job)
+		}
+		cancel()
+
+		select {
+		case <-s.jobs.wakeup:
+		case <-time.After(s.jobs.interval):
+		}
+	}
+}
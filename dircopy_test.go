@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// makeTree builds a directory to copy, returning it along with somewhere to copy it to
+func makeTree(t *testing.T, files ...string) (string, string) {
+	dir, _ := ioutil.TempDir("", "filecopier")
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	os.MkdirAll(dir+"/in/empty", 0755)
+	for _, f := range files {
+		os.MkdirAll(filepath.Dir(dir+"/in/"+f), 0755)
+		ioutil.WriteFile(dir+"/in/"+f, []byte("contents of "+f), 0644)
+	}
+	return dir + "/in", dir + "/out"
+}
+
+func waitForJob(t *testing.T, s *Server, id string, expected pb.CopyStatus) *pb.DirJob {
+	for i := 0; i < 100; i++ {
+		resp, err := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: id})
+		if err == nil && resp.GetJob().GetStatus() == expected {
+			return resp.GetJob()
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+	t.Fatalf("Job %v never reached %v", id, expected)
+	return nil
+}
+
+func TestDirCopyMapsPaths(t *testing.T) {
+	s := InitTestServer()
+	runJobs(s)
+
+	in, out := makeTree(t, "top.txt", "a/b/deep.txt")
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, Key: 30, Callback: "caller"})
+	if err != nil {
+		t.Fatalf("Dir copy failed: %v", err)
+	}
+
+	job := waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+	if job.GetFilesTotal() != 2 || job.GetFilesComplete() != 2 || job.GetFilesFailed() != 0 || job.GetBytesTransferred() == 0 {
+		t.Errorf("Bad job: %v", job)
+	}
+
+	for _, f := range []string{"top.txt", "a/b/deep.txt"} {
+		data, err := ioutil.ReadFile(out + "/" + f)
+		if err != nil || string(data) != "contents of "+f {
+			t.Errorf("%v was not copied: %v, %v", f, string(data), err)
+		}
+	}
+	if info, err := os.Stat(out + "/empty"); err != nil || !info.IsDir() {
+		t.Errorf("Empty directory was not created: %v", err)
+	}
+
+	cb := waitForCallback(t, s)
+	if cb.GetId() != resp.GetId() || cb.GetKey() != 30 || cb.GetStatus() != pb.CopyStatus_COMPLETE {
+		t.Errorf("Bad callback: %v", cb)
+	}
+	if len(s.callbacker.(*testCallbacker).received()) != 1 {
+		t.Errorf("Files were called back on their own: %v", s.callbacker.(*testCallbacker).received())
+	}
+
+	byKey, _ := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Key: 30})
+	if byKey.GetJob().GetId() != resp.GetId() {
+		t.Errorf("Job was not found by key: %v", byKey)
+	}
+}
+
+func TestDirCopyAgain(t *testing.T) {
+	s := InitTestServer()
+	runJobs(s)
+	in, out := makeTree(t, "one.txt")
+
+	resp, _ := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL})
+	waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+
+	ioutil.WriteFile(in+"/one.txt", []byte("changed"), 0644)
+	resp, _ = s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL})
+	job := waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+	if job.GetFilesComplete() != 1 {
+		t.Errorf("Bad job: %v", job)
+	}
+	if data, _ := ioutil.ReadFile(out + "/one.txt"); string(data) != "changed" {
+		t.Errorf("Second copy did not copy: %q", data)
+	}
+}
+
+func TestDirCopyFeedsTheQueue(t *testing.T) {
+	s := InitTestServer()
+	var files []string
+	for i := 0; i < jobFeedLimit+5; i++ {
+		files = append(files, fmt.Sprintf("file%v.txt", i))
+	}
+	in, out := makeTree(t, files...)
+
+	resp, _ := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out})
+	s.advanceJob(context.Background(), s.jobs.find(resp.GetId(), 0))
+
+	job := s.jobs.find(resp.GetId(), 0)
+	if s.scheduler.len() != jobFeedLimit || len(job.GetPending()) != 5 || len(job.GetActive()) != jobFeedLimit || job.GetStatus() != pb.CopyStatus_IN_PROGRESS {
+		t.Errorf("Queue was not fed properly (%v queued): %v", s.scheduler.len(), job)
+	}
+
+	list, _ := s.ListQueue(context.Background(), &pb.ListQueueRequest{})
+	for _, e := range list.GetEntries() {
+		if e.GetReq().GetOutputFile() != out+"/"+e.GetReq().GetInputFile()[len(in)+1:] || e.GetReq().GetPriority() != jobPriority {
+			t.Errorf("Bad child copy: %v", e.GetReq())
+		}
+	}
+
+	// A restart picks up where we left off
+	s = restartServer(t, s)
+	s.advanceJob(context.Background(), s.jobs.find(resp.GetId(), 0))
+	if job := s.jobs.find(resp.GetId(), 0); len(job.GetPending()) != 5 || len(job.GetActive()) != jobFeedLimit {
+		t.Errorf("Job was not restored: %v", job)
+	}
+}
+
+func TestDirCopyReportsFailures(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "one.txt", "two.txt", "three.txt")
+
+	resp, _ := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out})
+	s.advanceJob(context.Background(), s.jobs.find(resp.GetId(), 0))
+
+	s.queueMutex.Lock()
+	for i, q := range s.queue {
+		s.scheduler.remove(q)
+		q.resp.Status = pb.CopyStatus_COMPLETE
+		switch i {
+		case 0:
+			q.resp.Status = pb.CopyStatus_FAILED
+			q.resp.Error = "No such file"
+		case 1:
+			q.resp.Status = pb.CopyStatus_VERIFY_FAILED
+			q.resp.Error = "Bad checksum"
+		}
+	}
+	s.queueMutex.Unlock()
+
+	s.advanceJob(context.Background(), s.jobs.find(resp.GetId(), 0))
+	job := s.jobs.find(resp.GetId(), 0)
+	if job.GetStatus() != pb.CopyStatus_FAILED || job.GetFilesComplete() != 1 || job.GetFilesFailed() != 2 || job.GetError() != "Bad checksum" || job.GetTimeFinished() == 0 {
+		t.Errorf("Bad job: %v", job)
+	}
+}
+
+func TestDirCopyOfAFile(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "one.txt")
+
+	_, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in + "/one.txt", OutputFile: out})
+	if status.Convert(err).Code() != codes.FailedPrecondition {
+		t.Errorf("Copied a file as a directory: %v", err)
+	}
+
+	_, err = s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in + "/madeup", OutputFile: out})
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Copied a missing directory: %v", err)
+	}
+}
+
+func TestDirCopyFilters(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "main.go", "main.go.tmp", ".main.go.swp", ".git/HEAD", ".git/refs/main", "docs/readme.md", "lib/util.go")
+
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out,
+		Include: []string{"*.go", "*.tmp"}, Exclude: []string{"*.tmp", ".*.swp", ".git/"}})
+	if err != nil {
+		t.Fatalf("Dir copy failed: %v", err)
+	}
+
+	// main.go.tmp, .main.go.swp, .git and docs/readme.md are left out
+	if resp.GetFilesSkipped() != 4 {
+		t.Errorf("Bad skip count: %v", resp)
+	}
+
+	job := s.jobs.find(resp.GetId(), 0)
+	var files, dirs []string
+	for _, f := range job.GetPending() {
+		files = append(files, f.GetPath())
+	}
+	for _, d := range job.GetDirs() {
+		dirs = append(dirs, d.GetPath())
+	}
+	if len(files) != 2 || files[0] != "lib/util.go" || files[1] != "main.go" {
+		t.Errorf("Bad files: %v", files)
+	}
+	if len(dirs) != 2 || dirs[0] != "." || dirs[1] != "lib" {
+		t.Errorf("Bad directories: %v", dirs)
+	}
+
+	_, err = s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Exclude: []string{"["}})
+	if status.Convert(err).Code() != codes.InvalidArgument {
+		t.Errorf("Bad pattern was accepted: %v", err)
+	}
+}
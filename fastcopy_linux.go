@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+	"golang.org/x/sys/unix"
+)
+
+// rangeSize is the most copied in one go, so a cancelled copy stops promptly
+const rangeSize = 8 * 1024 * 1024
+
+// copyContents copies a file on this server. A filesystem which can share the
+// data between the files does that, otherwise the kernel copies it around any
+// holes in the source, and if it can't we copy it ourselves.
+func copyContents(ctx context.Context, dst, src *os.File, size int64, copied *int64) error {
+	if err := unix.IoctlFileClone(int(dst.Fd()), int(src.Fd())); err == nil {
+		atomic.StoreInt64(copied, size)
+		return nil
+	}
+
+	kernel := true
+	for off := int64(0); off < size; {
+		start, err := unix.Seek(int(src.Fd()), off, unix.SEEK_DATA)
+		if errors.Is(err, unix.ENXIO) {
+			// It's a hole from here to the end
+			break
+		}
+		if err != nil {
+			start = off
+		}
+		end, err := unix.Seek(int(src.Fd()), start, unix.SEEK_HOLE)
+		if err != nil || end > size {
+			end = size
+		}
+		atomic.AddInt64(copied, start-off)
+
+		for start < end {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			n := end - start
+			if n > rangeSize {
+				n = rangeSize
+			}
+
+			var written int64
+			if kernel {
+				roff, woff := start, start
+				w, err := unix.CopyFileRange(int(src.Fd()), &roff, int(dst.Fd()), &woff, int(n), 0)
+				if errors.Is(err, unix.EXDEV) || errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.EINVAL) {
+					kernel = false
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				written = int64(w)
+			} else {
+				written, err = io.Copy(io.NewOffsetWriter(dst, start), io.NewSectionReader(src, start, n))
+				if err != nil {
+					return err
+				}
+			}
+
+			// The file has been cut short under us
+			if written == 0 {
+				return io.ErrUnexpectedEOF
+			}
+			start += written
+			atomic.AddInt64(copied, written)
+		}
+		off = end
+	}
+
+	// Anything left is a hole, which comes from extending the file
+	if err := dst.Truncate(size); err != nil {
+		return err
+	}
+	atomic.StoreInt64(copied, size)
+	return nil
+}
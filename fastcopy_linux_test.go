@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+)
+
+func TestLocalCopyKeepsHoles(t *testing.T) {
+	s := InitTestServer()
+	dir := t.TempDir()
+
+	f, _ := os.Create(dir + "/sparse.img")
+	f.WriteAt([]byte("start"), 0)
+	f.WriteAt([]byte("middle"), 16*1024*1024)
+	f.Truncate(64 * 1024 * 1024)
+	f.Close()
+
+	resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/sparse.img", OutputFile: dir + "/copy.img", Transport: pb.TransportType_LOCAL})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if resp.GetBytesTransferred() != 64*1024*1024 {
+		t.Errorf("Bad progress: %v", resp.GetBytesTransferred())
+	}
+
+	in, _ := ioutil.ReadFile(dir + "/sparse.img")
+	out, _ := ioutil.ReadFile(dir + "/copy.img")
+	if !bytes.Equal(in, out) {
+		t.Errorf("Copy does not match")
+	}
+
+	info, _ := os.Stat(dir + "/copy.img")
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && st.Blocks*512 >= info.Size() {
+		t.Errorf("Holes were filled in: %v blocks for %v bytes", st.Blocks, info.Size())
+	}
+}
+
+func TestDefaultTransportCopiesLocally(t *testing.T) {
+	s := InitTestServer()
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT].(*scpTransport).command = "/does/not/exist"
+	dir := t.TempDir()
+	ioutil.WriteFile(dir+"/in.txt", []byte("local"), 0644)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+	if err != nil {
+		t.Fatalf("Local copy ran scp: %v", err)
+	}
+	if data, _ := ioutil.ReadFile(dir + "/out.txt"); string(data) != "local" {
+		t.Errorf("Bad copy: %q", data)
+	}
+}
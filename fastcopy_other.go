@@ -0,0 +1,16 @@
+//go:build !linux
+
+package main
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+)
+
+// copyContents copies a file on this server
+func copyContents(ctx context.Context, dst, src *os.File, size int64, copied *int64) error {
+	_, err := io.Copy(&countingWriter{w: dst, count: copied}, &contextReader{ctx: ctx, r: src})
+	return err
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -19,6 +18,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	pb "github.com/brotherlogic/filecopier/proto"
 	pbg "github.com/brotherlogic/goserver/proto"
@@ -33,9 +33,18 @@ var (
 )
 
 type queueEntry struct {
-	req       *pb.CopyRequest
-	resp      *pb.CopyResponse
-	timeAdded time.Time
+	req          *pb.CopyRequest
+	resp         *pb.CopyResponse
+	timeAdded    time.Time
+	timeStarted  time.Time
+	timeFinished time.Time
+
+	// cancel stops the copy if it's running, cancelled is set once it's been asked to
+	cancel    context.CancelFunc
+	cancelled bool
+
+	// watchers are sent an event whenever the entry changes
+	watchers []chan *pb.CopyEvent
 }
 
 type writer interface {
@@ -81,7 +90,11 @@ type Server struct {
 	keys            map[string]string
 	checker         checker
 	writer          writer
-	command         string
+	callbacker      callbacker
+	transports      map[pb.TransportType]transport
+	fs              fileSystem
+	journal         *journal
+	temps           *tempFiles
 	mykey           string
 	copies          int64
 	lastError       string
@@ -92,8 +105,20 @@ type Server struct {
 	copyTime        time.Duration
 	queue           []*queueEntry
 	tCopyTime       time.Duration
-	queueChan       chan *queueEntry
+	scheduler       *scheduler
 	current         *pb.CopyRequest
+	queueMutex      *sync.Mutex
+	queueLog        *queueLog
+	limits          limits
+	inputCopies     map[string]int
+	outputCopies    map[string]int
+	writing         map[string]bool
+	deadLetters     []*queueEntry
+	deadLetterLog   *queueLog
+	outbox          *outbox
+	transfers       map[*pb.CopyRequest]transfer
+	jobs            *dirJobs
+	delta           transport
 }
 
 // Init builds the server
@@ -103,7 +128,11 @@ func Init() *Server {
 		make(map[string]string),
 		&prodChecker{},
 		&prodWriter{file: "/home/simon/.ssh/authorized_keys"},
-		"/usr/bin/scp",
+		&prodCallbacker{},
+		make(map[pb.TransportType]transport),
+		&prodFileSystem{},
+		&journal{dir: "/home/simon/.filecopier/journal"},
+		&tempFiles{file: "/home/simon/.filecopier/tempfiles"},
 		"madeup",
 		int64(0),
 		"",
@@ -114,11 +143,34 @@ func Init() *Server {
 		0,
 		make([]*queueEntry, 0),
 		0,
-		make(chan *queueEntry, 100),
+		newScheduler(),
+		nil,
+		&sync.Mutex{},
+		&queueLog{file: "/home/simon/.filecopier/queue"},
+		limits{global: 4, perInput: 2, perOutput: 2},
+		make(map[string]int),
+		make(map[string]int),
+		make(map[string]bool),
+		nil,
+		&queueLog{file: "/home/simon/.filecopier/deadletters"},
+		newOutbox("/home/simon/.filecopier/outbox"),
+		make(map[*pb.CopyRequest]transfer),
+		newDirJobs("/home/simon/.filecopier/jobs"),
 		nil,
 	}
 
 	s.checker = &prodChecker{dial: s.FDialSpecificServer}
+	s.callbacker = &prodCallbacker{dial: s.FDial}
+	s.fs = &prodFileSystem{dial: s.FDialSpecificServer, isLocal: s.isLocal, journal: s.journal}
+
+	local := &localTransport{isLocal: s.isLocal}
+	stream := &grpcTransport{dial: s.FDialSpecificServer, isLocal: s.isLocal, journal: s.journal}
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &scpTransport{command: "/usr/bin/scp", copyString: s.makeCopyString, isLocal: s.isLocal, local: local, stream: stream}
+	s.transports[pb.TransportType_SCP] = &scpTransport{command: "/usr/bin/scp", copyString: s.makeCopyString, isLocal: s.isLocal}
+	s.transports[pb.TransportType_RSYNC] = &rsyncTransport{command: "/usr/bin/rsync", copyString: s.makeCopyString, isLocal: s.isLocal}
+	s.transports[pb.TransportType_LOCAL] = local
+	s.delta = &deltaTransport{dial: s.FDialSpecificServer, isLocal: s.isLocal}
+	s.transports[pb.TransportType_GRPC_STREAM] = stream
 	return s
 }
 
@@ -196,17 +248,49 @@ func (s *Server) shareKeys(ctx context.Context) error {
 	return nil
 }
 
+// queueRetention is how long a finished copy is kept around to be reported on
+const queueRetention = time.Minute * 5
+
+// expired is true if the entry finished long enough ago to be dropped from the queue
+func expired(entry *queueEntry) bool {
+	if !finished(entry.resp.GetStatus()) {
+		return false
+	}
+	done := entry.timeFinished
+	if done.IsZero() {
+		done = entry.timeAdded
+	}
+	return time.Now().Sub(done) >= queueRetention
+}
+
 func (s *Server) cleanQueue(ctx context.Context) error {
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
 	newQueue := s.queue
 	s.queue = nil
 
+	var entries []*pb.QueueEntry
 	for _, elem := range newQueue {
-		if elem.resp.Status != pb.CopyStatus_COMPLETE || time.Now().Sub(elem.timeAdded) < time.Minute*5 {
+		if !expired(elem) {
 			s.queue = append(s.queue, elem)
+			entries = append(entries, elem.toProto())
 		}
 	}
 
-	return nil
+	return s.queueLog.rewrite(entries)
+}
+
+// runCleaner clears finished copies out of the queue once they're done with
+func (s *Server) runCleaner() {
+	for {
+		time.Sleep(time.Minute)
+		ctx, cancel := utils.ManualContext("fc-clean", time.Minute)
+		if err := s.cleanQueue(ctx); err != nil {
+			s.CtxLog(ctx, fmt.Sprintf("Unable to clean the queue: %v", err))
+		}
+		cancel()
+	}
 }
 
 var (
@@ -255,96 +339,192 @@ func (s *Server) procCopy(ctx context.Context, output string, in *pb.CopyRequest
 			if err != nil {
 				s.RaiseIssue("Redux copy failed", fmt.Sprintf("(%v) %v %v -> %v, %v", s.Registry.Identifier, fs[0], fs[1:], string(out), err))
 			}
-		} 
+		}
 	}
 }
 
-func (s *Server) runCopy(ctx context.Context, in *pb.CopyRequest) error {
-	s.current = in
+func (s *Server) runCopy(ctx context.Context, in *pb.CopyRequest, resp *pb.CopyResponse) error {
 	copies.With(prometheus.Labels{"file": in.InputFile, "destination": in.OutputServer}).Inc()
 	stTime := time.Now()
+	s.ccopiesMutex.Lock()
+	s.current = in
 	s.lastCopyTime = time.Now()
 	s.lastCopyDetails = fmt.Sprintf("%v from %v to %v (%v)", in.InputFile, in.InputServer, in.OutputServer, in.OutputFile)
-	s.ccopies++
-	defer s.reduce()
+	s.copies++
+	s.ccopiesMutex.Unlock()
 
 	s.CtxLog(ctx, fmt.Sprintf("COPY: %v, %v to %v, %v", in.InputServer, in.InputFile, in.OutputServer, in.OutputFile))
-	s.copies++
 
-	err := s.checker.check(ctx, in.InputServer)
+	tr, err := s.getTransport(ctx, in)
 	if err != nil {
-		s.lastError = fmt.Sprintf("IN: %v", err)
-		return status.Errorf(status.Convert(err).Code(), "Input %v is unable to handle this request: %v", in.InputServer, err)
+		s.setError(fmt.Sprintf("TR %v", err))
+		return err
 	}
 
-	err = s.checker.check(ctx, in.OutputServer)
-	if err != nil {
-		s.lastError = fmt.Sprintf("OUT: %v", err)
-		return status.Errorf(status.Convert(err).Code(), "Output %v is unable to handle this request: %v", in.OutputServer, err)
+	if tr.sshKeys() {
+		err = s.checker.check(ctx, in.InputServer)
+		if err != nil {
+			s.setError(fmt.Sprintf("IN: %v", err))
+			return status.Errorf(status.Convert(err).Code(), "Input %v is unable to handle this request: %v", in.InputServer, err)
+		}
+
+		err = s.checker.check(ctx, in.OutputServer)
+		if err != nil {
+			s.setError(fmt.Sprintf("OUT: %v", err))
+			return status.Errorf(status.Convert(err).Code(), "Output %v is unable to handle this request: %v", in.OutputServer, err)
+		}
 	}
 
 	copyIn := s.makeCopyString(in.InputServer, in.InputFile)
 	copyOut := s.makeCopyString(in.OutputServer, in.OutputFile)
-	command := exec.Command(s.command, "-p", "-o", "StrictHostKeyChecking=no", copyIn, copyOut)
 
-	output := ""
-	out, err := command.StderrPipe()
-	if err == nil && out != nil {
-		scanner := bufio.NewScanner(out)
-		go func() {
-			for scanner != nil && scanner.Scan() {
-				output += scanner.Text()
+	if in.GetSymlinks() != pb.SymlinkPolicy_FOLLOW_SYMLINKS {
+		if done, err := s.copyLink(ctx, in, resp); done {
+			if err != nil {
+				s.setError(fmt.Sprintf("LN %v", err))
 			}
-			out.Close()
-		}()
+			return err
+		}
+	}
 
+	// Reading a fifo or a device would never finish, or never stop
+	if done, err := s.copySpecial(ctx, in, resp); done {
+		if err != nil {
+			s.setError(fmt.Sprintf("SF %v", err))
+		} else {
+			s.CtxLog(ctx, fmt.Sprintf("Skipping %v -> %v, it's a special file", copyIn, copyOut))
+		}
+		return err
 	}
 
-	err = command.Start()
+	if in.GetCondition() != pb.CopyCondition_ALWAYS_COPY {
+		skip, err := s.skipCopy(ctx, in)
+		if err != nil {
+			s.setError(fmt.Sprintf("CC %v", err))
+			return err
+		}
+		if skip {
+			s.CtxLog(ctx, fmt.Sprintf("Skipping %v -> %v, %v is already there", copyIn, copyOut, in.GetOutputFile()))
+			resp.Status = pb.CopyStatus_SKIPPED
+			return nil
+		}
+	}
+
+	md, err := s.sourceMetadata(ctx, in)
+	if err != nil {
+		s.setError(fmt.Sprintf("MD %v", err))
+		return err
+	}
+
+	// Copy into a temp file alongside the output and only move it into place once it's good
+	tin := proto.Clone(in).(*pb.CopyRequest)
+	tin.OutputFile = tempPath(in.GetOutputFile())
+	err = s.temps.add(in.GetOutputServer(), tin.GetOutputFile())
+	if err != nil {
+		s.setError(fmt.Sprintf("TF %v", err))
+		return status.Errorf(codes.Internal, "Unable to track temp file %v: %v", tin.GetOutputFile(), err)
+	}
+
+	output := ""
+	running, err := tr.start(ctx, tin)
 	if err != nil {
-		s.lastError = fmt.Sprintf("CS %v", err)
+		s.setError(fmt.Sprintf("CS %v", err))
 		s.procCopy(ctx, output, in)
 		s.CtxLog(ctx, fmt.Sprintf("Error running copy: %v, %v -> %v (%v)", copyIn, copyOut, err, output))
-		return status.Errorf(codes.Internal, "Error running copy: %v, %v -> %v (%v)", copyIn, copyOut, err, output)
+		if _, ok := status.FromError(err); ok {
+			return s.abandon(ctx, tin, err)
+		}
+		return s.abandon(ctx, tin, status.Errorf(classifyCopy(ctx, tr, err, output), "Error running copy: %v, %v -> %v (%v)", copyIn, copyOut, err, output))
+	}
+	s.trackTransfer(in, running)
+	defer s.untrackTransfer(in)
+	stopWatching := s.watchLongCopy(in, running, stTime)
+
+	output, err = running.wait()
+	stopWatching()
+	resp.BytesTransferred, _ = running.progress()
+	if saving, ok := running.(savingTransfer); ok {
+		resp.BytesSaved = saving.bytesSaved()
 	}
-	err = command.Wait()
 
 	if err != nil {
-		s.lastError = fmt.Sprintf("CW %v", err)
+		s.setError(fmt.Sprintf("CW %v", err))
 		s.procCopy(ctx, output, in)
 		s.CtxLog(ctx, fmt.Sprintf("Error waiting on copy: %v, %v -> %v (%v)", copyIn, copyOut, err, output))
-		return status.Errorf(codes.Internal, "Error waiting on copy: %v, %v -> %v (%v)", copyIn, copyOut, err, output)
+		return s.abandon(ctx, tin, status.Errorf(classifyCopy(ctx, tr, err, output), "Error waiting on copy: %v, %v -> %v (%v)", copyIn, copyOut, err, output))
 	}
 
 	s.procCopy(ctx, output, in)
 
-	s.copyTime = time.Now().Sub(stTime)
-	s.tCopyTime += time.Now().Sub(stTime)
+	if !in.GetSkipVerify() {
+		err = s.verify(ctx, tin, resp)
+		if err != nil {
+			s.setError(fmt.Sprintf("VF %v", err))
+			s.CtxLog(ctx, fmt.Sprintf("Error verifying copy: %v -> %v: %v", copyIn, copyOut, err))
+			return s.abandon(ctx, tin, err)
+		}
+	}
 
-	if s.copyTime > time.Hour {
-		s.RaiseIssue("Long Copy Time", fmt.Sprintf("Copy from %v to %v took %v", in.InputServer, in.OutputServer, s.copyTime))
+	err = s.applyMetadata(ctx, tin, md, resp)
+	if err != nil {
+		s.setError(fmt.Sprintf("MD %v", err))
+		s.CtxLog(ctx, fmt.Sprintf("Error setting metadata: %v -> %v: %v", copyIn, copyOut, err))
+		return s.abandon(ctx, tin, err)
 	}
 
-	s.lastError = fmt.Sprintf("DONE %v", output)
-	s.CtxLog(ctx, fmt.Sprintf("Completed %v -> %v with %v in %v", copyIn, copyOut, output, s.copyTime))
-	s.CtxLog(ctx, fmt.Sprintf("Calling back: %v", in.GetCallback()))
-
-	//Perform the callback if needed - this is fire and forget
-	if len(in.GetCallback()) > 0 {
-		conn, err := s.FDial(in.GetCallback())
-		if err == nil {
-			defer conn.Close()
-			s.CtxLog(ctx, fmt.Sprintf("Callingback: %v", in.GetCallback()))
-			client := pb.NewFileCopierCallbackClient(conn)
-			client.Callback(ctx, &pb.CallbackRequest{Key: in.GetKey()})
-		}
+	err = s.fs.rename(ctx, in.GetOutputServer(), tin.GetOutputFile(), in.GetOutputFile())
+	if err != nil {
+		s.setError(fmt.Sprintf("RN %v", err))
+		s.CtxLog(ctx, fmt.Sprintf("Error moving copy into place: %v -> %v: %v", copyIn, copyOut, err))
+		return s.abandon(ctx, tin, err)
 	}
+	s.temps.done(in.GetOutputServer(), tin.GetOutputFile())
 
+	copyTime := time.Now().Sub(stTime)
+	s.ccopiesMutex.Lock()
+	s.copyTime = copyTime
+	s.tCopyTime += copyTime
+	s.ccopiesMutex.Unlock()
+
+	s.setError(fmt.Sprintf("DONE %v", output))
+	s.CtxLog(ctx, fmt.Sprintf("Completed %v -> %v with %v in %v", copyIn, copyOut, output, copyTime))
 	return nil
 }
 
+// classifyCopy works out the error code of a failed copy, a copy which
+// failed because it was cancelled or timed out is reported as such
+func classifyCopy(ctx context.Context, tr transport, err error, output string) codes.Code {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Code()
+	}
+	return tr.classify(err, output)
+}
+
+// abandon removes the temp file of a failed copy, unless the failure is one
+// that will be retried and the partial file can be resumed from
+func (s *Server) abandon(ctx context.Context, in *pb.CopyRequest, err error) error {
+	if status.Convert(err).Code() != codes.Unavailable {
+		// A cancelled copy still needs tidying up
+		if ctx.Err() != nil {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+		}
+
+		if rerr := s.fs.remove(ctx, in.GetOutputServer(), in.GetOutputFile()); rerr != nil {
+			s.CtxLog(ctx, fmt.Sprintf("Unable to remove %v from %v: %v", in.GetOutputFile(), in.GetOutputServer(), rerr))
+		} else {
+			s.temps.done(in.GetOutputServer(), in.GetOutputFile())
+		}
+	}
+	return err
+}
+
 func main() {
 	var quiet = flag.Bool("quiet", false, "Show all output")
+	var workers = flag.Int("workers", 4, "The number of copies to run at once")
+	var perInput = flag.Int("per_input", 2, "The number of copies to run at once from any one server")
+	var perOutput = flag.Int("per_output", 2, "The number of copies to run at once to any one server")
 	flag.Parse()
 
 	//Turn off logging
@@ -353,6 +533,7 @@ func main() {
 		log.SetOutput(ioutil.Discard)
 	}
 	server := Init()
+	server.limits = limits{global: *workers, perInput: *perInput, perOutput: *perOutput}
 	server.PrepServer("filecopier")
 	server.Register = server
 
@@ -376,8 +557,37 @@ func main() {
 			dial:   server.FDialSpecificServer,
 		}
 
-		// Run the queue processor
+		// Pick up the queue from the last run, and clear out anything left
+		// half copied that isn't going to be resumed
+		ctx, cancel = utils.ManualContext("fc-clean", time.Minute)
+		err = server.loadQueue(ctx)
+		if err != nil {
+			fmt.Printf("Unable to load the queue: %v", err)
+			cancel()
+			return
+		}
+		err = server.outbox.load()
+		if err != nil {
+			fmt.Printf("Unable to load the callback outbox: %v", err)
+			cancel()
+			return
+		}
+		err = server.jobs.load()
+		if err != nil {
+			fmt.Printf("Unable to load the directory copies: %v", err)
+			cancel()
+			return
+		}
+		for _, err := range server.temps.cleanup(ctx, server.fs, server.queuedTemp) {
+			server.CtxLog(ctx, fmt.Sprintf("Temp cleanup: %v", err))
+		}
+		cancel()
+
+		// Run the queue processor, directory copies and anything waiting to be called back
 		go server.runQueue()
+		go server.runCleaner()
+		go server.runCallbacks()
+		go server.runJobs()
 
 		if server.Registry.Identifier == "rdisplay" {
 			server.NoProm = true
@@ -2,8 +2,8 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -13,6 +13,7 @@ import (
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 const name = "filecopier"
@@ -61,21 +62,6 @@ func (s *Server) Accepts(ctx context.Context, in *pb.AcceptsRequest) (*pb.Accept
 	return &pb.AcceptsResponse{Type: "key-passed"}, s.writer.writeKeys(s.keys)
 }
 
-func (s *Server) reduce() {
-	s.ccopiesMutex.Lock()
-	s.ccopies--
-	s.ccopiesMutex.Unlock()
-}
-
-// DirCopy copies a directory
-func (s *Server) DirCopy(ctx context.Context, in *pb.CopyRequest) (*pb.CopyResponse, error) {
-	err := filepath.Walk(in.InputFile, func(path string, info os.FileInfo, walkerr error) error {
-		s.QueueCopy(ctx, &pb.CopyRequest{InputServer: in.InputServer, InputFile: path, OutputServer: in.OutputServer, OutputFile: in.OutputFile + path, Priority: 100})
-		return nil
-	})
-	return &pb.CopyResponse{}, err
-}
-
 var (
 	queue = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "filecopier_queued",
@@ -85,53 +71,122 @@ var (
 
 // QueueCopy copies over a key using a queue
 func (s *Server) QueueCopy(ctx context.Context, in *pb.CopyRequest) (*pb.CopyResponse, error) {
-	if len(s.queueChan) > 20 {
+	if s.scheduler.len() > 20 {
 		return nil, status.Errorf(codes.ResourceExhausted, "Queue is full")
 	}
+	if err := validatePolicy(in.GetRetryPolicy()); err != nil {
+		return nil, err
+	}
+	if err := validateMetadata(in.GetMetadata()); err != nil {
+		return nil, err
+	}
+	if in.GetDryRun() {
+		return s.Copy(ctx, in)
+	}
+	return s.enqueue(ctx, in)
+}
+
+// sameCopy is true if both requests copy the same file to the same place
+func sameCopy(a, b *pb.CopyRequest) bool {
+	return a.GetInputServer() == b.GetInputServer() && a.GetOutputServer() == b.GetOutputServer() &&
+		a.GetInputFile() == b.GetInputFile() && a.GetOutputFile() == b.GetOutputFile()
+}
+
+// enqueue adds a copy to the queue, replacing any earlier copy of the same file
+// that isn't already running
+func (s *Server) enqueue(ctx context.Context, in *pb.CopyRequest) (*pb.CopyResponse, error) {
+	id := newID()
+	var replaced []*queueEntry
+	var callbacks []*pb.CallbackRequest
+
+	s.queueMutex.Lock()
+	// A copy that's already running can't be replaced, it'd be writing the same file
+	for _, q := range s.queue {
+		if sameCopy(in, q.req) && !finished(q.resp.GetStatus()) && s.scheduler.position(q) < 0 {
+			q.resp.IndexInQueue = 0
+			s.CtxLog(ctx, fmt.Sprintf("Already running (%v): %v", q.req, q.resp))
+			resp := proto.Clone(q.resp).(*pb.CopyResponse)
+			s.queueMutex.Unlock()
+			return resp, nil
+		}
+	}
 
 	var nq []*queueEntry
 	for ind, q := range s.queue {
-		if in.InputServer == q.req.InputServer && in.OutputServer == q.req.OutputServer &&
-			in.InputFile == q.req.InputFile && in.OutputFile == q.req.OutputFile {
-			if !in.GetOverride() && q.resp.Status == pb.CopyStatus_COMPLETE {
-				q.resp.IndexInQueue = int32(ind)
+		if sameCopy(in, q.req) {
+			if !in.GetOverride() && (q.resp.Status == pb.CopyStatus_COMPLETE || q.resp.Status == pb.CopyStatus_SKIPPED) {
+				q.resp.IndexInQueue = s.indexInQueue(q)
 				var err error
 				if len(q.resp.GetError()) > 0 {
 					err = status.Errorf(codes.Code(q.resp.GetErrorCode()), "%v", q.resp.GetError())
 				}
 				s.CtxLog(ctx, fmt.Sprintf("Found (%v) in queue: %v -> %v", q.req, ind, q.resp))
-				return q.resp, err
+				resp := proto.Clone(q.resp).(*pb.CopyResponse)
+				s.queueMutex.Unlock()
+				return resp, err
+			}
+			// Anyone following a copy we're replacing needs to hear it's not going to run
+			if s.scheduler.remove(q) {
+				q.resp.Status = pb.CopyStatus_CANCELLED
+				q.resp.Error = fmt.Sprintf("Replaced by %v", id)
+				q.resp.ErrorCode = int32(codes.Canceled)
+				q.timeFinished = time.Now()
+				s.notify(q)
+				replaced = append(replaced, q)
+				callbacks = append(callbacks, callbackFor(q))
 			}
+			s.unpersist(ctx, q)
 		} else {
 			nq = append(nq, q)
 		}
 	}
 	s.queue = nq
 
-	r := &pb.CopyResponse{Status: pb.CopyStatus_IN_QUEUE, TimeInQueue: time.Now().UnixNano()}
+	r := &pb.CopyResponse{Status: pb.CopyStatus_IN_QUEUE, TimeInQueue: time.Now().UnixNano(), Id: id, Priority: in.GetPriority()}
 	entry := &queueEntry{req: in, resp: r, timeAdded: time.Now()}
 	queue.With(prometheus.Labels{"file": in.InputFile, "destination": in.OutputServer}).Inc()
 	s.queue = append(s.queue, entry)
-	s.queueChan <- entry
-	s.CtxLog(ctx, fmt.Sprintf("Added to queue: %v", len(s.queueChan)))
-	return r, nil
+	s.persist(ctx, entry)
+	s.scheduler.push(entry, entry.timeAdded)
+	r.IndexInQueue = s.indexInQueue(entry)
+	resp := proto.Clone(r).(*pb.CopyResponse)
+	s.queueMutex.Unlock()
+
+	for i, q := range replaced {
+		s.sendCallback(ctx, q.req, callbacks[i])
+	}
+	s.CtxLog(ctx, fmt.Sprintf("Added to queue: %v", s.scheduler.len()))
+	return resp, nil
 }
 
 // Copy copies over a key
 func (s *Server) Copy(ctx context.Context, in *pb.CopyRequest) (*pb.CopyResponse, error) {
-	s.ccopiesMutex.Lock()
-	if s.ccopies > 0 {
-		s.ccopiesMutex.Unlock()
-		return nil, fmt.Errorf("Too many concurrent copies from %v (%+v)", s.Registry.Identifier, s.current)
+	if err := validateMetadata(in.GetMetadata()); err != nil {
+		return nil, err
+	}
+	if in.GetDryRun() {
+		plan := &pb.Plan{}
+		s.planCopy(ctx, in, plan)
+		return &pb.CopyResponse{Plan: plan}, nil
 	}
 
-	s.ccopies++
-	s.ccopiesMutex.Unlock()
+	if !s.acquire(in) {
+		return nil, s.tooBusy(in)
+	}
+	defer s.release(in)
 
 	t := time.Now()
-	err := s.runCopy(ctx, in)
-	defer s.reduce()
-	return &pb.CopyResponse{MillisToCopy: time.Now().Sub(t).Nanoseconds() / 1000000}, err
+	resp := &pb.CopyResponse{}
+	err := s.runCopy(ctx, in, resp)
+	resp.MillisToCopy = time.Now().Sub(t).Nanoseconds() / 1000000
+
+	cb := &pb.CallbackRequest{Key: in.GetKey(), Status: finalStatus(err, resp), BytesTransferred: resp.GetBytesTransferred(), MillisToCopy: resp.GetMillisToCopy()}
+	if err != nil {
+		cb.Error = fmt.Sprintf("%v", err)
+		cb.ErrorCode = int32(status.Convert(err).Code())
+	}
+	s.sendCallback(ctx, in, cb)
+	return resp, err
 }
 
 func (s *Server) Exists(ctx context.Context, req *pb.ExistsRequest) (*pb.ExistsResponse, error) {
@@ -150,20 +205,339 @@ func (s *Server) Replicate(ctx context.Context, req *pb.ReplicateRequest) (*pb.R
 	if err != nil {
 		return nil, err
 	}
+
+	plan := &pb.Plan{}
 	for _, se := range servers {
 		if !strings.HasPrefix(se, s.Registry.Identifier) {
 			elems := strings.Split(se, ":")
-			_, err = s.Copy(ctx, &pb.CopyRequest{
+			in := &pb.CopyRequest{
 				OutputFile:   req.GetPath(),
 				OutputServer: elems[0],
 				InputFile:    req.GetPath(),
 				InputServer:  s.Registry.Identifier,
-			})
+			}
+			if req.GetDryRun() {
+				s.planCopy(ctx, in, plan)
+				continue
+			}
+
+			_, err = s.Copy(ctx, in)
 			if err != nil {
 				return nil, err
 			}
 		}
 	}
 
+	if req.GetDryRun() {
+		return &pb.ReplicateResponse{Servers: int32(len(servers)), Plan: plan}, nil
+	}
 	return &pb.ReplicateResponse{Servers: int32(len(servers))}, nil
 }
+
+// PushFile receives a file streamed from another filecopier
+func (s *Server) PushFile(stream pb.FileCopierService_PushFileServer) error {
+	chunk, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if len(chunk.GetPath()) == 0 {
+		return status.Errorf(codes.InvalidArgument, "The first chunk must carry a path")
+	}
+
+	sink := &fileSink{path: chunk.GetPath(), journal: s.journal}
+	for {
+		if err := sink.send(chunk); err != nil {
+			sink.abort()
+			return status.Errorf(classifyFile(err), "Unable to write %v: %v", sink.path, err)
+		}
+
+		chunk, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			sink.abort()
+			return err
+		}
+	}
+
+	if err := sink.close(); err != nil {
+		return status.Errorf(classifyFile(err), "Unable to complete %v: %v", sink.path, err)
+	}
+	return stream.SendAndClose(&pb.PushFileResponse{BytesWritten: sink.written})
+}
+
+// PullFile streams a file to another filecopier
+func (s *Server) PullFile(req *pb.PullFileRequest, stream pb.FileCopierService_PullFileServer) error {
+	source, err := openFileSource(req.GetPath(), &pb.TransferJournal{Offset: req.GetOffset(), Size: req.GetSize(), ModTime: req.GetModTime()})
+	if err != nil {
+		return status.Errorf(classifyFile(err), "Unable to read %v: %v", req.GetPath(), err)
+	}
+	defer source.close()
+
+	for {
+		chunk, err := source.recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(classifyFile(err), "Unable to read %v: %v", req.GetPath(), err)
+		}
+
+		if err := stream.Send(chunk); err != nil {
+			return err
+		}
+	}
+}
+
+// Signature describes the blocks of a file on this server, for a delta transfer to it
+func (s *Server) Signature(ctx context.Context, req *pb.SignatureRequest) (*pb.Signature, error) {
+	return signatureFile(req.GetPath(), req.GetBlockSize())
+}
+
+// Delta streams what another filecopier needs to rebuild a file on this server from its basis
+func (s *Server) Delta(req *pb.DeltaRequest, stream pb.FileCopierService_DeltaServer) error {
+	f, err := os.Open(req.GetPath())
+	if err != nil {
+		return status.Errorf(classifyFile(err), "Unable to read %v: %v", req.GetPath(), err)
+	}
+	defer f.Close()
+
+	if err := computeDelta(f, req.GetBasis(), stream.Send); err != nil {
+		return status.Errorf(classifyFile(err), "Unable to read %v: %v", req.GetPath(), err)
+	}
+	return nil
+}
+
+// ApplyDelta rebuilds a file on this server from a basis and a delta
+func (s *Server) ApplyDelta(stream pb.FileCopierService_ApplyDeltaServer) error {
+	op, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if len(op.GetPath()) == 0 || len(op.GetBasisPath()) == 0 {
+		return status.Errorf(codes.InvalidArgument, "The first op must carry a path and a basis")
+	}
+
+	sink := &fileDelta{basisPath: op.GetBasisPath(), path: op.GetPath()}
+	for {
+		if err := sink.send(op); err != nil {
+			sink.abort()
+			return status.Errorf(classifyFile(err), "Unable to write %v: %v", sink.path, err)
+		}
+
+		op, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			sink.abort()
+			return err
+		}
+	}
+
+	resp, err := sink.close()
+	if err != nil {
+		return status.Errorf(classifyFile(err), "Unable to complete %v: %v", sink.path, err)
+	}
+	return stream.SendAndClose(resp)
+}
+
+// GetResumeOffset reports how much of an interrupted transfer we already have
+func (s *Server) GetResumeOffset(ctx context.Context, req *pb.ResumeRequest) (*pb.TransferJournal, error) {
+	return s.journal.get(req.GetPath()), nil
+}
+
+// Checksum computes the checksum of a file on this server
+func (s *Server) Checksum(ctx context.Context, req *pb.ChecksumRequest) (*pb.ChecksumResponse, error) {
+	return checksumFile(req.GetPath(), req.GetAlgorithm())
+}
+
+// Rename moves a file on this server
+func (s *Server) Rename(ctx context.Context, req *pb.RenameRequest) (*pb.RenameResponse, error) {
+	return &pb.RenameResponse{}, renameFile(req.GetFrom(), req.GetTo())
+}
+
+// Remove deletes a file on this server
+func (s *Server) Remove(ctx context.Context, req *pb.RemoveRequest) (*pb.RemoveResponse, error) {
+	return &pb.RemoveResponse{}, removeFile(s.journal, req.GetPath())
+}
+
+// ListDir lists everything under a directory on this server
+func (s *Server) ListDir(ctx context.Context, req *pb.ListDirRequest) (*pb.ListDirResponse, error) {
+	return listDir(req)
+}
+
+// Stat describes a path on this server
+func (s *Server) Stat(ctx context.Context, req *pb.StatRequest) (*pb.StatResponse, error) {
+	return statPath(req.GetPath())
+}
+
+// Link makes a hard link on this server
+func (s *Server) Link(ctx context.Context, req *pb.LinkRequest) (*pb.LinkResponse, error) {
+	return &pb.LinkResponse{}, linkFile(req.GetTarget(), req.GetPath())
+}
+
+// Symlink makes a symbolic link on this server
+func (s *Server) Symlink(ctx context.Context, req *pb.SymlinkRequest) (*pb.SymlinkResponse, error) {
+	return &pb.SymlinkResponse{}, symlinkFile(req.GetTarget(), req.GetPath())
+}
+
+// GetMetadata reads the metadata of a file on this server that a policy preserves
+func (s *Server) GetMetadata(ctx context.Context, req *pb.GetMetadataRequest) (*pb.GetMetadataResponse, error) {
+	md, err := readMetadata(req.GetPath(), req.GetPolicy())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetMetadataResponse{Metadata: md}, nil
+}
+
+// SetMetadata sets the metadata of a file on this server
+func (s *Server) SetMetadata(ctx context.Context, req *pb.SetMetadataRequest) (*pb.SetMetadataResponse, error) {
+	md, err := writeMetadata(req.GetPath(), req.GetMetadata())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetMetadataResponse{Metadata: md}, nil
+}
+
+// MakeDir creates a directory, along with any parents, on this server
+func (s *Server) MakeDir(ctx context.Context, req *pb.MakeDirRequest) (*pb.MakeDirResponse, error) {
+	return &pb.MakeDirResponse{}, makeDir(req.GetPath(), req.GetMode())
+}
+
+// snapshot copies out the state of an entry, call with the queue lock held
+func (s *Server) snapshot(entry *queueEntry) *pb.QueueEntry {
+	s.updateProgress(entry)
+	p := proto.Clone(entry.toProto()).(*pb.QueueEntry)
+	p.Resp.IndexInQueue = s.indexInQueue(entry)
+	if len(entry.req.GetCallback()) > 0 {
+		p.Callback = s.outbox.get(entry.resp.GetId())
+	}
+	return p
+}
+
+// GetCopyStatus reports on a single queued copy, or on a directory copy as a whole
+func (s *Server) GetCopyStatus(ctx context.Context, req *pb.CopyStatusRequest) (*pb.CopyStatusResponse, error) {
+	if len(req.GetId()) == 0 && req.GetKey() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "You need to supply an id or a key")
+	}
+
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
+	q := s.findEntry(req.GetId(), req.GetKey())
+	if q == nil {
+		if job := s.jobs.find(req.GetId(), req.GetKey()); job != nil {
+			return &pb.CopyStatusResponse{Job: job}, nil
+		}
+		return nil, status.Errorf(codes.NotFound, "No copy found for %v", req)
+	}
+	return &pb.CopyStatusResponse{Entry: s.snapshot(q)}, nil
+}
+
+// findEntry looks up an entry by id, or the latest with the key if there's no id,
+// falling back to the dead letters, call with the queue lock held
+func (s *Server) findEntry(id string, key int64) *queueEntry {
+	for _, entries := range [][]*queueEntry{s.queue, s.deadLetters} {
+		for i := len(entries) - 1; i >= 0; i-- {
+			q := entries[i]
+			if (len(id) > 0 && q.resp.GetId() == id) || (len(id) == 0 && q.req.GetKey() == key) {
+				return q
+			}
+		}
+	}
+	return nil
+}
+
+// ListQueue lists the copies in the queue
+func (s *Server) ListQueue(ctx context.Context, req *pb.ListQueueRequest) (*pb.ListQueueResponse, error) {
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
+	resp := &pb.ListQueueResponse{Paused: s.scheduler.isPaused()}
+	for _, q := range s.queue {
+		if matchesFilter(req, q) {
+			resp.Entries = append(resp.Entries, s.snapshot(q))
+		}
+	}
+	return resp, nil
+}
+
+func matchesFilter(req *pb.ListQueueRequest, q *queueEntry) bool {
+	if len(req.GetStatus()) > 0 {
+		found := false
+		for _, st := range req.GetStatus() {
+			if st == q.resp.GetStatus() {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return (len(req.GetInputServer()) == 0 || req.GetInputServer() == q.req.GetInputServer()) &&
+		(len(req.GetOutputServer()) == 0 || req.GetOutputServer() == q.req.GetOutputServer()) &&
+		(req.GetKey() == 0 || req.GetKey() == q.req.GetKey())
+}
+
+// CancelCopy stops a copy, either taking it out of the queue or killing it if it's running
+func (s *Server) CancelCopy(ctx context.Context, req *pb.CancelCopyRequest) (*pb.CancelCopyResponse, error) {
+	if len(req.GetId()) == 0 && req.GetKey() == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "You need to supply an id or a key")
+	}
+
+	s.queueMutex.Lock()
+	q := s.findEntry(req.GetId(), req.GetKey())
+	if q == nil {
+		s.queueMutex.Unlock()
+		return nil, status.Errorf(codes.NotFound, "No copy found for %v", req)
+	}
+
+	removed := false
+	switch q.resp.GetStatus() {
+	case pb.CopyStatus_IN_QUEUE:
+		q.cancelled = true
+		if s.scheduler.remove(q) {
+			removed = true
+			q.resp.Status = pb.CopyStatus_CANCELLED
+			q.timeFinished = time.Now()
+			s.persist(ctx, q)
+		}
+	case pb.CopyStatus_IN_PROGRESS:
+		q.cancelled = true
+		if q.cancel != nil {
+			q.cancel()
+		}
+	default:
+		s.queueMutex.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition, "Copy %v has already finished: %v", q.resp.GetId(), q.resp.GetStatus())
+	}
+	resp := &pb.CancelCopyResponse{Entry: s.snapshot(q)}
+	cb := callbackFor(q)
+	s.queueMutex.Unlock()
+
+	// Clear out anything left by an earlier attempt
+	if removed {
+		tin := proto.Clone(q.req).(*pb.CopyRequest)
+		tin.OutputFile = tempPath(q.req.GetOutputFile())
+		s.abandon(ctx, tin, status.Errorf(codes.Canceled, "Cancelled"))
+		s.sendCallback(ctx, q.req, cb)
+	}
+
+	s.CtxLog(ctx, fmt.Sprintf("Cancelled %v", resp.GetEntry().GetResp().GetId()))
+	return resp, nil
+}
+
+// PauseQueue stops any more queued copies from starting
+func (s *Server) PauseQueue(ctx context.Context, req *pb.PauseQueueRequest) (*pb.PauseQueueResponse, error) {
+	s.scheduler.pause()
+	return &pb.PauseQueueResponse{}, nil
+}
+
+// ResumeQueue restarts a paused queue
+func (s *Server) ResumeQueue(ctx context.Context, req *pb.ResumeQueueRequest) (*pb.ResumeQueueResponse, error) {
+	s.scheduler.resume()
+	return &pb.ResumeQueueResponse{}, nil
+}
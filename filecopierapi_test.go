@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
 
 	pbd "github.com/brotherlogic/discovery/proto"
 	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type testChecker struct {
@@ -23,6 +27,73 @@ func (t *testChecker) check(ctx context.Context, server string) error {
 	return fmt.Errorf("FAIL")
 }
 
+type testFileSystem struct {
+	journal *journal
+}
+
+func (t *testFileSystem) checksum(ctx context.Context, server, path string, algorithm pb.HashAlgorithm) (*pb.ChecksumResponse, error) {
+	return checksumFile(path, algorithm)
+}
+
+func (t *testFileSystem) rename(ctx context.Context, server, from, to string) error {
+	return renameFile(from, to)
+}
+
+func (t *testFileSystem) remove(ctx context.Context, server, path string) error {
+	return removeFile(t.journal, path)
+}
+
+func (t *testFileSystem) list(ctx context.Context, server string, req *pb.ListDirRequest) (*pb.ListDirResponse, error) {
+	return listDir(req)
+}
+
+func (t *testFileSystem) stat(ctx context.Context, server, path string) (*pb.StatResponse, error) {
+	return statPath(path)
+}
+
+func (t *testFileSystem) link(ctx context.Context, server, target, path string) error {
+	return linkFile(target, path)
+}
+
+func (t *testFileSystem) symlink(ctx context.Context, server, target, path string) error {
+	return symlinkFile(target, path)
+}
+
+func (t *testFileSystem) getMetadata(ctx context.Context, server, path string, policy *pb.MetadataPolicy) (*pb.FileMetadata, error) {
+	return readMetadata(path, policy)
+}
+
+func (t *testFileSystem) setMetadata(ctx context.Context, server, path string, md *pb.FileMetadata) (*pb.FileMetadata, error) {
+	return writeMetadata(path, md)
+}
+
+func (t *testFileSystem) makeDir(ctx context.Context, server, path string, mode uint32) error {
+	return makeDir(path, mode)
+}
+
+type testCallbacker struct {
+	mutex    sync.Mutex
+	calls    []*pb.CallbackRequest
+	failures int
+}
+
+func (t *testCallbacker) callback(ctx context.Context, server string, req *pb.CallbackRequest) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.failures != 0 {
+		t.failures--
+		return status.Errorf(codes.Unavailable, "Callback failed")
+	}
+	t.calls = append(t.calls, req)
+	return nil
+}
+
+func (t *testCallbacker) received() []*pb.CallbackRequest {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]*pb.CallbackRequest{}, t.calls...)
+}
+
 type testWriter struct{}
 
 func (t *testWriter) writeKeys(map[string]string) error {
@@ -33,9 +104,22 @@ func InitTestServer() *Server {
 	s := Init()
 	s.writer = &testWriter{}
 	s.checker = &testChecker{}
+	s.callbacker = &testCallbacker{}
+	s.fs = &testFileSystem{journal: s.journal}
 	s.SkipLog = true
 	s.SkipIssue = true
 	s.Registry = &pbd.RegistryEntry{}
+	s.journal.dir, _ = ioutil.TempDir("", "filecopier-journal")
+	s.temps.file = s.journal.dir + "/tempfiles"
+	s.queueLog.file = s.journal.dir + "/queue"
+	s.deadLetterLog.file = s.journal.dir + "/deadletters"
+	s.outbox.file = s.journal.dir + "/outbox"
+	s.jobs.file = s.journal.dir + "/jobs"
+
+	// There's no discovery to find peers to stream to
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT].(*scpTransport).stream = &grpcTransport{dial: func(ctx context.Context, job, server string) (*grpc.ClientConn, error) {
+		return nil, status.Errorf(codes.Unavailable, "No filecopier on %v", server)
+	}, isLocal: s.isLocal, journal: s.journal}
 
 	return s
 }
@@ -85,7 +169,7 @@ func TestCopyFailCommand(t *testing.T) {
 	d := []byte("testing")
 	ioutil.WriteFile("test.txt", d, 0644)
 	dir, _ := os.Getwd()
-	s.command = "blah"
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &scpTransport{command: "blah", copyString: s.makeCopyString, isLocal: s.isLocal}
 	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: fmt.Sprintf("%v/test.txt", dir), OutputFile: fmt.Sprintf("%v/testout.txt", dir)})
 
 	if err == nil {
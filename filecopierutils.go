@@ -4,39 +4,13 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"sort"
+	"path/filepath"
 	"strings"
-	"time"
 
-	pb "github.com/brotherlogic/filecopier/proto"
-	"github.com/brotherlogic/goserver/utils"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"golang.org/x/net/context"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
-func (s *Server) sortQueue(ctx context.Context) {
-	for _, q := range s.queue {
-		if q == nil {
-			s.RaiseIssue("Empty Element in QUeue", fmt.Sprintf("%v", s.queue))
-			return
-		}
-	}
-
-	sort.SliceStable(s.queue, func(i, j int) bool {
-		if s.queue[i].resp.GetStatus() == pb.CopyStatus_IN_QUEUE && s.queue[j].resp.GetStatus() != pb.CopyStatus_IN_QUEUE {
-			return true
-		}
-		if s.queue[i].resp.Status != pb.CopyStatus_IN_QUEUE && s.queue[j].resp.Status == pb.CopyStatus_IN_QUEUE {
-			return false
-		}
-		return s.queue[i].resp.Priority < s.queue[j].resp.Priority
-	})
-
-}
-
 var (
 	retries = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "filecopier_retries",
@@ -44,34 +18,17 @@ var (
 	})
 )
 
-func (s *Server) runQueue() {
-	for entry := range s.queueChan {
-		entry.resp.Status = pb.CopyStatus_IN_PROGRESS
-		ctx, cancel := utils.ManualContext(fmt.Sprintf("copy-for-%v", entry.req.InputFile), time.Hour)
-		err := s.runCopy(ctx, entry.req)
-		if status.Convert(err).Code() == codes.Unavailable {
-			s.CtxLog(ctx, fmt.Sprintf("CopyFailed %v", entry))
-			entry.resp.Status = pb.CopyStatus_IN_QUEUE
-			entry.resp.Repeats++
-			if entry.resp.Repeats < 10 {
-				retries.Inc()
-				s.queueChan <- entry
-			}
-		} else {
-			if err != nil {
-				entry.resp.Error = fmt.Sprintf("%v", err)
-				entry.resp.ErrorCode = int32(status.Convert(err).Code())
-			}
-			entry.resp.Status = pb.CopyStatus_COMPLETE
-		}
-		cancel()
-
-		time.Sleep(time.Second)
+// indexInQueue is the number of copies which will run before this one
+func (s *Server) indexInQueue(entry *queueEntry) int32 {
+	pos := s.scheduler.position(entry)
+	if pos < 0 {
+		return 0
 	}
+	return int32(pos)
 }
 
 func (s *Server) makeCopyString(server, file string) string {
-	if len(server) == 0 || server == s.Registry.GetIdentifier() {
+	if s.isLocal(server) {
 		return file
 	}
 
@@ -114,3 +71,29 @@ func writeKeys(file string, keys map[string]string) error {
 	return nil
 
 }
+
+// writeFileAtomic replaces a file with the data, synced to disk before it goes
+// in place so a crash leaves either the old file or the new one
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
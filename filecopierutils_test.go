@@ -1,10 +1,9 @@
 package main
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
-
-	pb "github.com/brotherlogic/filecopier/proto"
-	"golang.org/x/net/context"
 )
 
 func TestBadRead(t *testing.T) {
@@ -60,50 +59,25 @@ func TestWriteKeys(t *testing.T) {
 	}
 }
 
-func TestSortQueue(t *testing.T) {
-	s := InitTestServer()
-	s.queue = append(s.queue, &queueEntry{resp: &pb.CopyResponse{Priority: 10}})
-	s.queue = append(s.queue, &queueEntry{resp: &pb.CopyResponse{Priority: 100}})
-
-	s.sortQueue(context.Background())
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/nested/state"
 
-	if s.queue[0].resp.Priority == 100 {
-		t.Errorf("Queue is missorted")
+	if err := writeFileAtomic(path, []byte("first")); err != nil {
+		t.Fatalf("Write failed: %v", err)
 	}
-}
-
-func TestSortQueueV1(t *testing.T) {
-	s := InitTestServer()
-	s.queue = append(s.queue, &queueEntry{resp: &pb.CopyResponse{Status: pb.CopyStatus_COMPLETE}})
-	s.queue = append(s.queue, &queueEntry{resp: &pb.CopyResponse{Status: pb.CopyStatus_IN_QUEUE}})
-
-	s.sortQueue(context.Background())
-
-	if s.queue[0].resp.Status == pb.CopyStatus_COMPLETE {
-		t.Errorf("Queue is missorted")
+	if err := writeFileAtomic(path, []byte("second")); err != nil {
+		t.Fatalf("Rewrite failed: %v", err)
 	}
-}
 
-func TestSortQueueV2(t *testing.T) {
-	s := InitTestServer()
-	s.queue = append(s.queue, &queueEntry{resp: &pb.CopyResponse{Status: pb.CopyStatus_IN_QUEUE}})
-	s.queue = append(s.queue, &queueEntry{resp: &pb.CopyResponse{Status: pb.CopyStatus_COMPLETE}})
-
-	s.sortQueue(context.Background())
-
-	if s.queue[0].resp.Status == pb.CopyStatus_COMPLETE {
-		t.Errorf("Queue is missorted")
+	data, err := ioutil.ReadFile(path)
+	if err != nil || string(data) != "second" {
+		t.Errorf("Bad contents: %q, %v", data, err)
 	}
-}
-
-func TestSortQueueNilElement(t *testing.T) {
-	s := InitTestServer()
-	s.queue = append(s.queue, &queueEntry{resp: &pb.CopyResponse{Status: pb.CopyStatus_IN_QUEUE}})
-	s.queue = append(s.queue, nil)
-
-	s.sortQueue(context.Background())
-
-	if s.queue[0].resp.Status == pb.CopyStatus_COMPLETE {
-		t.Errorf("Queue is missorted")
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("Temp file was left behind: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Bad mode: %v, %v", info, err)
 	}
 }
@@ -0,0 +1,468 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"github.com/cespare/xxhash/v2"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fileSystem runs file operations on a given server
+type fileSystem interface {
+	checksum(ctx context.Context, server, path string, algorithm pb.HashAlgorithm) (*pb.ChecksumResponse, error)
+	rename(ctx context.Context, server, from, to string) error
+	remove(ctx context.Context, server, path string) error
+	list(ctx context.Context, server string, req *pb.ListDirRequest) (*pb.ListDirResponse, error)
+	makeDir(ctx context.Context, server, path string, mode uint32) error
+	stat(ctx context.Context, server, path string) (*pb.StatResponse, error)
+	link(ctx context.Context, server, target, path string) error
+	symlink(ctx context.Context, server, target, path string) error
+	getMetadata(ctx context.Context, server, path string, policy *pb.MetadataPolicy) (*pb.FileMetadata, error)
+	setMetadata(ctx context.Context, server, path string, md *pb.FileMetadata) (*pb.FileMetadata, error)
+}
+
+// prodFileSystem works on local files directly and asks the filecopier on any other server
+type prodFileSystem struct {
+	dial    func(ctx context.Context, job, server string) (*grpc.ClientConn, error)
+	isLocal func(server string) bool
+	journal *journal
+}
+
+func (p *prodFileSystem) client(ctx context.Context, server string) (pb.FileCopierServiceClient, func() error, error) {
+	conn, err := p.dial(ctx, "filecopier", server)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pb.NewFileCopierServiceClient(conn), conn.Close, nil
+}
+
+func (p *prodFileSystem) checksum(ctx context.Context, server, path string, algorithm pb.HashAlgorithm) (*pb.ChecksumResponse, error) {
+	if p.isLocal(server) {
+		return checksumFile(path, algorithm)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return client.Checksum(ctx, &pb.ChecksumRequest{Path: path, Algorithm: algorithm})
+}
+
+func (p *prodFileSystem) rename(ctx context.Context, server, from, to string) error {
+	if p.isLocal(server) {
+		return renameFile(from, to)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return err
+	}
+	defer done()
+	_, err = client.Rename(ctx, &pb.RenameRequest{From: from, To: to})
+	return err
+}
+
+func (p *prodFileSystem) remove(ctx context.Context, server, path string) error {
+	if p.isLocal(server) {
+		return removeFile(p.journal, path)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return err
+	}
+	defer done()
+	_, err = client.Remove(ctx, &pb.RemoveRequest{Path: path})
+	return err
+}
+
+func (p *prodFileSystem) list(ctx context.Context, server string, req *pb.ListDirRequest) (*pb.ListDirResponse, error) {
+	if p.isLocal(server) {
+		return listDir(req)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return client.ListDir(ctx, req)
+}
+
+func (p *prodFileSystem) makeDir(ctx context.Context, server, path string, mode uint32) error {
+	if p.isLocal(server) {
+		return makeDir(path, mode)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return err
+	}
+	defer done()
+	_, err = client.MakeDir(ctx, &pb.MakeDirRequest{Path: path, Mode: mode})
+	return err
+}
+
+func (p *prodFileSystem) stat(ctx context.Context, server, path string) (*pb.StatResponse, error) {
+	if p.isLocal(server) {
+		return statPath(path)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return client.Stat(ctx, &pb.StatRequest{Path: path})
+}
+
+func (p *prodFileSystem) link(ctx context.Context, server, target, path string) error {
+	if p.isLocal(server) {
+		return linkFile(target, path)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return err
+	}
+	defer done()
+	_, err = client.Link(ctx, &pb.LinkRequest{Target: target, Path: path})
+	return err
+}
+
+func (p *prodFileSystem) symlink(ctx context.Context, server, target, path string) error {
+	if p.isLocal(server) {
+		return symlinkFile(target, path)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return err
+	}
+	defer done()
+	_, err = client.Symlink(ctx, &pb.SymlinkRequest{Target: target, Path: path})
+	return err
+}
+
+func (p *prodFileSystem) getMetadata(ctx context.Context, server, path string, policy *pb.MetadataPolicy) (*pb.FileMetadata, error) {
+	if p.isLocal(server) {
+		return readMetadata(path, policy)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	resp, err := client.GetMetadata(ctx, &pb.GetMetadataRequest{Path: path, Policy: policy})
+	return resp.GetMetadata(), err
+}
+
+func (p *prodFileSystem) setMetadata(ctx context.Context, server, path string, md *pb.FileMetadata) (*pb.FileMetadata, error) {
+	if p.isLocal(server) {
+		return writeMetadata(path, md)
+	}
+
+	client, done, err := p.client(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	resp, err := client.SetMetadata(ctx, &pb.SetMetadataRequest{Path: path, Metadata: md})
+	return resp.GetMetadata(), err
+}
+
+// statPath describes a path, along with whether we can write where it is, or
+// would go, and how much room there is there
+func statPath(path string) (*pb.StatResponse, error) {
+	resp := &pb.StatResponse{}
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		if resp.Symlink, err = os.Readlink(path); err != nil {
+			return nil, status.Errorf(classifyFile(err), "Unable to read link %v: %v", path, err)
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err == nil {
+		resp.Exists = true
+		resp.IsDir = info.IsDir()
+		resp.Size = info.Size()
+		resp.Mode = uint32(info.Mode().Perm())
+		resp.ModTime = info.ModTime().UnixNano()
+		resp.Special = !info.Mode().IsRegular() && !info.IsDir()
+	} else if !os.IsNotExist(err) {
+		return nil, status.Errorf(classifyFile(err), "Unable to stat %v: %v", path, err)
+	}
+
+	// A copy writes next to the output, which may be a few directories down from anything that exists
+	dir := path
+	if !resp.IsDir {
+		dir = filepath.Dir(path)
+	}
+	for _, err := os.Stat(dir); os.IsNotExist(err) && dir != filepath.Dir(dir); _, err = os.Stat(dir) {
+		dir = filepath.Dir(dir)
+	}
+
+	resp.Writable, resp.FreeBytes = dirSpace(dir)
+	return resp, nil
+}
+
+// noID is the id of a file we can't identify
+var noID = [2]uint64{}
+
+// lister walks a directory for listDir
+type lister struct {
+	req    *pb.ListDirRequest
+	filter *filter
+	resp   *pb.ListDirResponse
+	dirs   []*pb.FileEntry
+	wanted map[string]bool
+
+	// The first file seen for each inode, for hard links
+	inodes map[[2]uint64]string
+
+	// The directories we're in, so a followed link can't send us round in circles
+	visiting map[[2]uint64]bool
+}
+
+// addFile lists a file, along with the directories it's in
+func (l *lister) addFile(e *pb.FileEntry) {
+	l.resp.Entries = append(l.resp.Entries, e)
+	for dir := filepath.Dir(e.GetPath()); !l.wanted[dir]; dir = filepath.Dir(dir) {
+		l.wanted[dir] = true
+	}
+}
+
+func (l *lister) walk(file, rel string, info os.FileInfo) error {
+	// The patterns are always matched against slash separated paths
+	name := filepath.ToSlash(rel)
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		switch l.req.GetSymlinks() {
+		case pb.SymlinkPolicy_SKIP_SYMLINKS:
+			l.resp.Skipped++
+			return nil
+		case pb.SymlinkPolicy_COPY_SYMLINKS:
+			if l.filter.excluded(name, false) || !l.filter.included(name) {
+				l.resp.Skipped++
+				return nil
+			}
+			target, err := os.Readlink(file)
+			if err != nil {
+				return err
+			}
+			l.addFile(&pb.FileEntry{Path: rel, Symlink: target})
+			return nil
+		}
+
+		// A followed link is listed as whatever it points to, which may not be there
+		var err error
+		info, err = os.Stat(file)
+		if os.IsNotExist(err) {
+			l.resp.Skipped++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if rel != "." && l.filter.excluded(name, info.IsDir()) {
+		l.resp.Skipped++
+		return nil
+	}
+
+	switch {
+	case info.IsDir():
+		// Without inodes to go on we can't tell when we've been round in a circle
+		if id, _ := fileID(info); id != noID {
+			if l.visiting[id] {
+				l.resp.Skipped++
+				return nil
+			}
+			l.visiting[id] = true
+			defer delete(l.visiting, id)
+		}
+
+		l.dirs = append(l.dirs, &pb.FileEntry{Path: rel, IsDir: true, Mode: uint32(info.Mode().Perm())})
+		entries, err := os.ReadDir(file)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			child, err := e.Info()
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := l.walk(filepath.Join(file, e.Name()), filepath.Join(rel, e.Name()), child); err != nil {
+				return err
+			}
+		}
+	case info.Mode().IsRegular():
+		if !l.filter.included(name) {
+			l.resp.Skipped++
+			return nil
+		}
+		e := &pb.FileEntry{Path: rel, Size: info.Size(), Mode: uint32(info.Mode().Perm())}
+		if id, links := fileID(info); l.req.GetPreserveHardlinks() && links > 1 {
+			if first, ok := l.inodes[id]; ok {
+				e.Hardlink = first
+			} else {
+				l.inodes[id] = rel
+			}
+		}
+		l.addFile(e)
+	case l.req.GetSpecialFiles() == pb.SpecialFilePolicy_FAIL_ON_SPECIAL_FILES:
+		return status.Errorf(codes.FailedPrecondition, "%v is a special file (%v)", file, info.Mode().Type())
+	default:
+		l.resp.Skipped++
+	}
+	return nil
+}
+
+// listDir walks a directory, listing the directories, regular files and links
+// under it which get through the request's patterns and policies
+func listDir(req *pb.ListDirRequest) (*pb.ListDirResponse, error) {
+	root := req.GetPath()
+	f, err := newFilter(req.GetInclude(), req.GetExclude())
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, status.Errorf(classifyFile(err), "Unable to list %v: %v", root, err)
+	}
+	if !info.IsDir() {
+		return nil, status.Errorf(codes.FailedPrecondition, "%v is not a directory", root)
+	}
+
+	l := &lister{
+		req:      req,
+		filter:   f,
+		resp:     &pb.ListDirResponse{},
+		wanted:   map[string]bool{".": true},
+		inodes:   make(map[[2]uint64]string),
+		visiting: make(map[[2]uint64]bool),
+	}
+	if err := l.walk(root, ".", info); err != nil {
+		return nil, status.Errorf(classifyFile(err), "Unable to list %v: %v", root, err)
+	}
+
+	// With includes we only want the directories which have something in them
+	for _, d := range l.dirs {
+		if len(req.GetInclude()) == 0 || l.wanted[d.GetPath()] {
+			l.resp.Entries = append(l.resp.Entries, d)
+		}
+	}
+	return l.resp, nil
+}
+
+// makeLink puts a link in place of whatever's at the path, by making it alongside and moving it over
+func makeLink(path string, link func(tmp string) error) error {
+	tmp := tempPath(path)
+	os.Remove(tmp)
+	if err := link(tmp); err != nil {
+		return status.Errorf(classifyFile(err), "Unable to link %v: %v", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return status.Errorf(classifyFile(err), "Unable to link %v: %v", path, err)
+	}
+
+	// Moving a link over another link to the same file leaves both in place
+	os.Remove(tmp)
+	return nil
+}
+
+func linkFile(target, path string) error {
+	return makeLink(path, func(tmp string) error { return os.Link(target, tmp) })
+}
+
+func symlinkFile(target, path string) error {
+	return makeLink(path, func(tmp string) error { return os.Symlink(target, tmp) })
+}
+
+func makeDir(path string, mode uint32) error {
+	if mode == 0 {
+		mode = 0755
+	}
+	if err := os.MkdirAll(path, os.FileMode(mode)); err != nil {
+		return status.Errorf(classifyFile(err), "Unable to make %v: %v", path, err)
+	}
+	return nil
+}
+
+func newHash(algorithm pb.HashAlgorithm) (hash.Hash, error) {
+	switch algorithm {
+	case pb.HashAlgorithm_SHA256:
+		return sha256.New(), nil
+	case pb.HashAlgorithm_XXHASH64:
+		return xxhash.New(), nil
+	}
+	return nil, status.Errorf(codes.InvalidArgument, "Unknown hash algorithm %v", algorithm)
+}
+
+func checksumFile(path string, algorithm pb.HashAlgorithm) (*pb.ChecksumResponse, error) {
+	h, err := newHash(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, status.Errorf(classifyFile(err), "Unable to checksum %v: %v", path, err)
+	}
+	defer f.Close()
+
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return nil, status.Errorf(classifyFile(err), "Unable to checksum %v: %v", path, err)
+	}
+	return &pb.ChecksumResponse{Checksum: fmt.Sprintf("%x", h.Sum(nil)), Size: size}, nil
+}
+
+// verify checks that both ends of a copy have the same contents
+func (s *Server) verify(ctx context.Context, in *pb.CopyRequest, resp *pb.CopyResponse) error {
+	source, err := s.fs.checksum(ctx, in.GetInputServer(), in.GetInputFile(), in.GetChecksumAlgorithm())
+	if status.Convert(err).Code() == codes.Unimplemented {
+		s.CtxLog(ctx, fmt.Sprintf("Unable to verify %v on %v, it does not support checksums", in.GetInputFile(), in.GetInputServer()))
+		return nil
+	}
+	if err != nil {
+		return status.Errorf(status.Convert(err).Code(), "Unable to checksum source %v: %v", in.GetInputFile(), err)
+	}
+
+	dest, err := s.fs.checksum(ctx, in.GetOutputServer(), in.GetOutputFile(), in.GetChecksumAlgorithm())
+	if status.Convert(err).Code() == codes.Unimplemented {
+		s.CtxLog(ctx, fmt.Sprintf("Unable to verify %v on %v, it does not support checksums", in.GetOutputFile(), in.GetOutputServer()))
+		return nil
+	}
+	if err != nil {
+		return status.Errorf(status.Convert(err).Code(), "Unable to checksum destination %v: %v", in.GetOutputFile(), err)
+	}
+
+	if source.GetChecksum() != dest.GetChecksum() || source.GetSize() != dest.GetSize() {
+		return status.Errorf(codes.DataLoss, "Checksum mismatch copying %v to %v: %v (%v bytes) vs %v (%v bytes)",
+			in.GetInputFile(), in.GetOutputFile(), source.GetChecksum(), source.GetSize(), dest.GetChecksum(), dest.GetSize())
+	}
+
+	resp.Checksum = dest.GetChecksum()
+	if resp.GetBytesTransferred() == 0 {
+		resp.BytesTransferred = dest.GetSize()
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// fileID identifies a file by its device and inode, along with how many links it has
+func fileID(info os.FileInfo) ([2]uint64, uint64) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return noID, 0
+	}
+	return [2]uint64{uint64(st.Dev), st.Ino}, uint64(st.Nlink)
+}
+
+// dirSpace reports whether we can write to a directory and how much room there is in it
+func dirSpace(dir string) (bool, int64) {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(dir, &fs); err != nil {
+		return unix.Access(dir, unix.W_OK) == nil, 0
+	}
+	return unix.Access(dir, unix.W_OK) == nil, int64(fs.Bavail) * int64(fs.Bsize)
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package main
+
+import (
+	"os"
+)
+
+// fileID identifies a file, which we can only do on linux, so hard links
+// aren't found and followed links aren't checked for loops
+func fileID(info os.FileInfo) ([2]uint64, uint64) {
+	return noID, 0
+}
+
+// dirSpace reports whether we can write to a directory and how much room
+// there is in it. We can't tell here, so the copy finds out for itself.
+func dirSpace(dir string) (bool, int64) {
+	return true, -1
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChecksum(t *testing.T) {
+	s := InitTestServer()
+	dir, _ := ioutil.TempDir("", "filecopier")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/in.txt", []byte("testing"), 0644)
+
+	resp, err := s.Checksum(context.Background(), &pb.ChecksumRequest{Path: dir + "/in.txt"})
+	if err != nil {
+		t.Fatalf("Checksum failed: %v", err)
+	}
+	if resp.GetChecksum() != "cf80cd8aed482d5d1527d7dc72fceff84e6326592848447d2dc0b0e87dfc9a90" || resp.GetSize() != 7 {
+		t.Errorf("Bad checksum: %v", resp)
+	}
+
+	resp, err = s.Checksum(context.Background(), &pb.ChecksumRequest{Path: dir + "/in.txt", Algorithm: pb.HashAlgorithm_XXHASH64})
+	if err != nil {
+		t.Fatalf("Checksum failed: %v", err)
+	}
+	if len(resp.GetChecksum()) != 16 {
+		t.Errorf("Bad xxhash: %v", resp)
+	}
+}
+
+func TestChecksumMissing(t *testing.T) {
+	s := InitTestServer()
+
+	_, err := s.Checksum(context.Background(), &pb.ChecksumRequest{Path: "madeup/in.txt"})
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Missing file was not reported: %v", err)
+	}
+}
+
+func TestCopyVerifies(t *testing.T) {
+	s := InitTestServer()
+	dir, _ := ioutil.TempDir("", "filecopier")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/in.txt", []byte("testing"), 0644)
+
+	resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if resp.GetChecksum() != "cf80cd8aed482d5d1527d7dc72fceff84e6326592848447d2dc0b0e87dfc9a90" {
+		t.Errorf("Checksum was not returned: %v", resp)
+	}
+}
+
+func TestCopyVerifyMismatch(t *testing.T) {
+	s := InitTestServer()
+	s.transports[pb.TransportType_RSYNC] = &testTransport{}
+	dir, _ := ioutil.TempDir("", "filecopier")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/in.txt", []byte("testing"), 0644)
+	ioutil.WriteFile(tempPath(dir+"/out.txt"), []byte("tasting"), 0644)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_RSYNC})
+	if status.Convert(err).Code() != codes.DataLoss {
+		t.Errorf("Mismatch was not reported: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"path"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pattern is a single gitignore style glob
+type pattern struct {
+	negate  bool
+	dirOnly bool
+	parts   []string
+}
+
+// parsePattern follows gitignore: a leading ! negates, a trailing / only matches
+// directories, and a pattern with no other / matches at any depth
+func parsePattern(p string) (*pattern, error) {
+	pat := &pattern{}
+	raw := p
+
+	p = strings.TrimSpace(p)
+	if strings.HasPrefix(p, "!") {
+		pat.negate = true
+		p = p[1:]
+	}
+	if strings.HasSuffix(p, "/") {
+		pat.dirOnly = true
+		p = strings.TrimSuffix(p, "/")
+	}
+
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if len(p) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Empty pattern %q", raw)
+	}
+
+	pat.parts = strings.Split(p, "/")
+	if !anchored {
+		pat.parts = append([]string{"**"}, pat.parts...)
+	}
+	for _, part := range pat.parts {
+		if _, err := path.Match(part, ""); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Bad pattern %q: %v", raw, err)
+		}
+	}
+	return pat, nil
+}
+
+// matches checks a slash separated path relative to the directory being copied
+func (p *pattern) matches(file string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	return matchParts(p.parts, strings.Split(file, "/"))
+}
+
+func matchParts(pat, name []string) bool {
+	for len(pat) > 0 {
+		if pat[0] == "**" {
+			// A trailing ** is everything inside, but not the directory itself
+			if len(pat) == 1 {
+				return len(name) > 0
+			}
+			for i := 0; i <= len(name); i++ {
+				if matchParts(pat[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pat[0], name[0]); !ok {
+			return false
+		}
+		pat, name = pat[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// filter picks out what a directory copy should copy
+type filter struct {
+	include []*pattern
+	exclude []*pattern
+}
+
+func newFilter(include, exclude []string) (*filter, error) {
+	f := &filter{}
+	for _, p := range include {
+		pat, err := parsePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, pat)
+	}
+	for _, p := range exclude {
+		pat, err := parsePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, pat)
+	}
+	return f, nil
+}
+
+// lastMatch runs through the patterns in order, the last one to match decides
+func lastMatch(patterns []*pattern, file string, isDir bool, current bool) bool {
+	for _, p := range patterns {
+		if p.matches(file, isDir) {
+			current = !p.negate
+		}
+	}
+	return current
+}
+
+// excluded is true if the file or directory should be left out, anything
+// under an excluded directory is left out along with it
+func (f *filter) excluded(file string, isDir bool) bool {
+	return lastMatch(f.exclude, file, isDir, false)
+}
+
+// included is true if a file, or one of the directories it's in, matches the includes
+func (f *filter) included(file string) bool {
+	if len(f.include) == 0 {
+		return true
+	}
+
+	parts := strings.Split(file, "/")
+	included := false
+	for i := range parts {
+		included = lastMatch(f.include, strings.Join(parts[:i+1], "/"), i < len(parts)-1, included)
+	}
+	return included
+}
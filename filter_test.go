@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		isDir   bool
+		matches bool
+	}{
+		{"*.tmp", "build.tmp", false, true},
+		{"*.tmp", "a/b/build.tmp", false, true},
+		{"*.tmp", "build.tmp.go", false, false},
+		{".git/", ".git", true, true},
+		{".git/", "sub/.git", true, true},
+		{".git/", ".git", false, false},
+		{".*.swp", "src/.main.go.swp", false, true},
+		{"/top.txt", "top.txt", false, true},
+		{"/top.txt", "a/top.txt", false, false},
+		{"a/*.txt", "a/one.txt", false, true},
+		{"a/*.txt", "b/a/one.txt", false, false},
+		{"a/**/z.txt", "a/z.txt", false, true},
+		{"a/**/z.txt", "a/b/c/z.txt", false, true},
+		{"**/logs", "x/y/logs", true, true},
+		{"out/**", "out/a/b", false, true},
+		{"out/**", "out", true, false},
+		{"file?.txt", "file1.txt", false, true},
+		{"[ab].txt", "c.txt", false, false},
+	}
+
+	for _, test := range tests {
+		p, err := parsePattern(test.pattern)
+		if err != nil {
+			t.Fatalf("Unable to parse %v: %v", test.pattern, err)
+		}
+		if p.matches(test.file, test.isDir) != test.matches {
+			t.Errorf("%v matching %v (dir %v) should be %v", test.pattern, test.file, test.isDir, test.matches)
+		}
+	}
+}
+
+func TestBadPatterns(t *testing.T) {
+	for _, p := range []string{"[", "", "!", "/"} {
+		if _, err := parsePattern(p); status.Convert(err).Code() != codes.InvalidArgument {
+			t.Errorf("%q was parsed: %v", p, err)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	f, err := newFilter([]string{"src/", "*.md"}, []string{"*.tmp", "!keep.tmp"})
+	if err != nil {
+		t.Fatalf("Bad filter: %v", err)
+	}
+
+	if !f.excluded("src/a.tmp", false) || f.excluded("src/keep.tmp", false) {
+		t.Errorf("Negation was not applied")
+	}
+	if !f.included("src/a/b.go") || !f.included("README.md") || f.included("bin/tool") {
+		t.Errorf("Includes were not applied")
+	}
+
+	if f, _ := newFilter(nil, nil); !f.included("anything") || f.excluded("anything", false) {
+		t.Errorf("Empty filter left something out")
+	}
+}
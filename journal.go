@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// journalEvery is the number of chunks written between journal updates
+const journalEvery = 16
+
+// journal records how far through each incoming file we've got, so that
+// an interrupted transfer can pick up from the last chunk we know is on disk
+type journal struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+func (j *journal) file(path string) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%x", sha256.Sum256([]byte(path))))
+}
+
+func (j *journal) get(path string) *pb.TransferJournal {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	data, err := ioutil.ReadFile(j.file(path))
+	if err != nil {
+		return &pb.TransferJournal{Path: path}
+	}
+
+	entry := &pb.TransferJournal{}
+	if err := proto.Unmarshal(data, entry); err != nil || entry.GetPath() != path {
+		return &pb.TransferJournal{Path: path}
+	}
+	return entry
+}
+
+func (j *journal) record(entry *pb.TransferJournal) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	data, err := proto.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(j.file(entry.GetPath()), data)
+}
+
+func (j *journal) clear(path string) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	err := os.Remove(j.file(path))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
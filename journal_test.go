@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestJournal(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "filecopier")
+	defer os.RemoveAll(dir)
+	j := &journal{dir: dir}
+
+	if j.get("/blah").GetOffset() != 0 {
+		t.Errorf("Empty journal has an offset: %v", j.get("/blah"))
+	}
+
+	err := j.record(&pb.TransferJournal{Path: "/blah", Offset: 100, Size: 200})
+	if err != nil {
+		t.Fatalf("Unable to record: %v", err)
+	}
+	if j.get("/blah").GetOffset() != 100 {
+		t.Errorf("Bad offset: %v", j.get("/blah"))
+	}
+
+	j.clear("/blah")
+	if j.get("/blah").GetOffset() != 0 {
+		t.Errorf("Journal was not cleared: %v", j.get("/blah"))
+	}
+}
+
+// partialCopy sets up a temp file which has a first chunk that differs from
+// the source, so that we can tell if it was resent - copies using this have to
+// skip verification
+func partialCopy(t *testing.T, s *Server, dir string) []byte {
+	in, _ := ioutil.ReadFile(dir + "/in.txt")
+	info, _ := os.Stat(dir + "/in.txt")
+
+	partial := bytes.Repeat([]byte("z"), chunkSize)
+	ioutil.WriteFile(tempPath(dir+"/out.txt"), partial, 0640)
+	s.journal.record(&pb.TransferJournal{Path: tempPath(dir + "/out.txt"), Offset: chunkSize, Size: info.Size(), ModTime: info.ModTime().Unix()})
+
+	return append(partial, in[chunkSize:]...)
+}
+
+func TestStreamResumePull(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	expected := partialCopy(t, s, dir)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputServer: "remote", InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM, SkipVerify: true})
+	if err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+
+	out, _ := ioutil.ReadFile(dir + "/out.txt")
+	if !bytes.Equal(out, expected) {
+		t.Errorf("Copy was not resumed")
+	}
+	if s.journal.get(tempPath(dir+"/out.txt")).GetOffset() != 0 {
+		t.Errorf("Journal was not cleared")
+	}
+}
+
+func TestStreamResumePush(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	expected := partialCopy(t, s, dir)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM, SkipVerify: true})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+
+	out, _ := ioutil.ReadFile(dir + "/out.txt")
+	if !bytes.Equal(out, expected) {
+		t.Errorf("Copy was not resumed")
+	}
+}
+
+func TestStreamResumeChangedSource(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	partialCopy(t, s, dir)
+	s.journal.record(&pb.TransferJournal{Path: tempPath(dir + "/out.txt"), Offset: chunkSize, Size: 12})
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	checkCopied(t, dir)
+}
+
+func TestStreamResumeMissingPartial(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	partialCopy(t, s, dir)
+	ioutil.WriteFile(tempPath(dir+"/out.txt"), []byte("short"), 0640)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if err == nil {
+		t.Fatalf("Resume of a short file did not fail")
+	}
+
+	_, err = s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	checkCopied(t, dir)
+}
+
+func TestDefaultResumesWithPeers(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &scpTransport{command: "/does/not/exist", copyString: s.makeCopyString, isLocal: s.isLocal,
+		stream: s.transports[pb.TransportType_GRPC_STREAM].(*grpcTransport)}
+	expected := partialCopy(t, s, dir)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/out.txt", SkipVerify: true})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+
+	out, _ := ioutil.ReadFile(dir + "/out.txt")
+	if !bytes.Equal(out, expected) {
+		t.Errorf("Copy was not resumed")
+	}
+}
+
+func TestDefaultSkipsKeyCheckWithPeers(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &scpTransport{command: "/does/not/exist", copyString: s.makeCopyString, isLocal: s.isLocal,
+		stream: s.transports[pb.TransportType_GRPC_STREAM].(*grpcTransport)}
+	s.checker = &testChecker{failServer: "remote"}
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/out.txt"})
+	if err != nil {
+		t.Fatalf("Streamed copy needed keys: %v", err)
+	}
+	checkCopied(t, dir)
+}
+
+func TestDefaultFallsBackToScp(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &scpTransport{command: "/does/not/exist", copyString: s.makeCopyString, isLocal: s.isLocal,
+		stream: &grpcTransport{dial: func(ctx context.Context, job, server string) (*grpc.ClientConn, error) {
+			return nil, status.Errorf(codes.Unavailable, "No filecopier on %v", server)
+		}, isLocal: s.isLocal, journal: s.journal}}
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/out.txt"})
+	if err == nil {
+		t.Errorf("Copy did not go through scp")
+	}
+
+	// scp still needs the keys
+	s.checker = &testChecker{failServer: "remote"}
+	_, err = s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/out.txt"})
+	if err == nil || !strings.Contains(err.Error(), "unable to handle") {
+		t.Errorf("Keys were not checked for scp: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// copyLink deals with a single file copy of a symbolic link that isn't to be
+// followed, it's true if there's nothing left for the transport to copy
+func (s *Server) copyLink(ctx context.Context, in *pb.CopyRequest, resp *pb.CopyResponse) (bool, error) {
+	st, err := s.fs.stat(ctx, in.GetInputServer(), in.GetInputFile())
+	if err != nil {
+		return true, status.Errorf(status.Convert(err).Code(), "Unable to check %v for links: %v", in.GetInputFile(), err)
+	}
+	if len(st.GetSymlink()) == 0 {
+		return false, nil
+	}
+
+	if in.GetSymlinks() == pb.SymlinkPolicy_SKIP_SYMLINKS {
+		resp.FilesSkipped = 1
+		resp.Status = pb.CopyStatus_SKIPPED
+		return true, nil
+	}
+	return true, s.fs.symlink(ctx, in.GetOutputServer(), st.GetSymlink(), in.GetOutputFile())
+}
+
+// copySpecial applies the special file policy to a single file copy, it's true
+// if the source is a special file, which there's nothing to be done with
+func (s *Server) copySpecial(ctx context.Context, in *pb.CopyRequest, resp *pb.CopyResponse) (bool, error) {
+	st, err := s.fs.stat(ctx, in.GetInputServer(), in.GetInputFile())
+	// A server which can't tell us gets its files copied as they always were
+	if status.Convert(err).Code() == codes.Unimplemented {
+		return false, nil
+	}
+	if err != nil {
+		return true, status.Errorf(status.Convert(err).Code(), "Unable to check %v: %v", in.GetInputFile(), err)
+	}
+	if !st.GetSpecial() {
+		return false, nil
+	}
+
+	if in.GetSpecialFiles() == pb.SpecialFilePolicy_FAIL_ON_SPECIAL_FILES {
+		return true, status.Errorf(codes.FailedPrecondition, "%v is a special file", in.GetInputFile())
+	}
+	resp.FilesSkipped = 1
+	resp.Status = pb.CopyStatus_SKIPPED
+	return true, nil
+}
+
+// makeLinks makes the links of a directory copy, stopping if the destination can't be reached
+func (s *Server) makeLinks(ctx context.Context, job *pb.DirJob) {
+	in := job.GetReq()
+	for len(job.Links) > 0 {
+		l := job.Links[0]
+		path := filepath.Join(in.GetOutputFile(), l.GetPath())
+
+		var err error
+		if len(l.GetSymlink()) > 0 {
+			err = s.fs.symlink(ctx, in.GetOutputServer(), l.GetSymlink(), path)
+		} else {
+			err = s.fs.link(ctx, in.GetOutputServer(), filepath.Join(in.GetOutputFile(), l.GetHardlink()), path)
+		}
+
+		switch {
+		case status.Convert(err).Code() == codes.Unavailable:
+			job.Error = fmt.Sprintf("%v", err)
+			return
+		case err != nil:
+			job.Error = fmt.Sprintf("%v", err)
+			job.FilesFailed++
+		default:
+			job.FilesComplete++
+		}
+		job.Links = job.Links[1:]
+	}
+}
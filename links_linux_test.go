@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func makeLinkTree(t *testing.T) (string, string) {
+	in, out := makeTree(t, "a.txt", "sub/b.txt")
+	os.Symlink("a.txt", in+"/link.txt")
+	os.Symlink("missing.txt", in+"/dangling.txt")
+	os.Symlink("..", in+"/sub/loop")
+	if err := unix.Mkfifo(in+"/fifo", 0644); err != nil {
+		t.Fatalf("Unable to make fifo: %v", err)
+	}
+	return in, out
+}
+
+func TestDirCopySymlinkPolicies(t *testing.T) {
+	for _, policy := range []pb.SymlinkPolicy{pb.SymlinkPolicy_FOLLOW_SYMLINKS, pb.SymlinkPolicy_COPY_SYMLINKS, pb.SymlinkPolicy_SKIP_SYMLINKS} {
+		s := InitTestServer()
+		runJobs(s)
+		in, out := makeLinkTree(t)
+
+		resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, Symlinks: policy})
+		if err != nil {
+			t.Fatalf("DirCopy with %v failed: %v", policy, err)
+		}
+		waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+
+		target, linkErr := os.Readlink(out + "/link.txt")
+		data, readErr := ioutil.ReadFile(out + "/link.txt")
+		_, fifoErr := os.Lstat(out + "/fifo")
+		switch policy {
+		case pb.SymlinkPolicy_FOLLOW_SYMLINKS:
+			// The fifo, the dangling link and the loop back up are all left out
+			if linkErr == nil || string(data) != "contents of a.txt" || resp.GetFilesSkipped() != 3 {
+				t.Errorf("Link was not followed: %v, %q, %v", linkErr, data, resp)
+			}
+		case pb.SymlinkPolicy_COPY_SYMLINKS:
+			if target != "a.txt" || string(data) != "contents of a.txt" || resp.GetFilesSkipped() != 1 {
+				t.Errorf("Link was not copied: %q, %q, %v", target, data, resp)
+			}
+			if dangling, err := os.Readlink(out + "/dangling.txt"); err != nil || dangling != "missing.txt" {
+				t.Errorf("Dangling link was not copied: %q, %v", dangling, err)
+			}
+		case pb.SymlinkPolicy_SKIP_SYMLINKS:
+			if readErr == nil || resp.GetFilesSkipped() != 4 {
+				t.Errorf("Link was not skipped: %v, %v", readErr, resp)
+			}
+		}
+		if !os.IsNotExist(fifoErr) {
+			t.Errorf("Fifo was copied with %v: %v", policy, fifoErr)
+		}
+	}
+}
+
+func TestDirCopyFailsOnSpecialFiles(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeLinkTree(t)
+
+	_, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, SpecialFiles: pb.SpecialFilePolicy_FAIL_ON_SPECIAL_FILES})
+	if status.Convert(err).Code() != codes.FailedPrecondition {
+		t.Errorf("Fifo did not fail the copy: %v", err)
+	}
+}
+
+func TestDirCopyPreservesHardlinks(t *testing.T) {
+	s := InitTestServer()
+	runJobs(s)
+	in, out := makeTree(t, "a.txt", "sub/c.txt")
+	os.Link(in+"/a.txt", in+"/sub/b.txt")
+
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, PreserveHardlinks: true})
+	if err != nil {
+		t.Fatalf("DirCopy failed: %v", err)
+	}
+	job := waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+	if job.GetFilesTotal() != 3 || job.GetFilesComplete() != 3 {
+		t.Errorf("Bad job: %v", job)
+	}
+
+	a, _ := os.Stat(out + "/a.txt")
+	b, _ := os.Stat(out + "/sub/b.txt")
+	c, _ := os.Stat(out + "/sub/c.txt")
+	if a == nil || b == nil || !os.SameFile(a, b) || os.SameFile(a, c) {
+		t.Errorf("Hard links were not preserved: %v, %v, %v", a, b, c)
+	}
+}
+
+func TestCopySymlink(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "a.txt")
+	os.MkdirAll(out, 0755)
+	os.Symlink(in+"/a.txt", in+"/link.txt")
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: in + "/link.txt", OutputFile: out + "/link.txt", Transport: pb.TransportType_LOCAL, Symlinks: pb.SymlinkPolicy_COPY_SYMLINKS})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	if target, err := os.Readlink(out + "/link.txt"); err != nil || target != in+"/a.txt" {
+		t.Errorf("Link was not copied: %q, %v", target, err)
+	}
+
+	resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: in + "/link.txt", OutputFile: out + "/skipped.txt", Transport: pb.TransportType_LOCAL, Symlinks: pb.SymlinkPolicy_SKIP_SYMLINKS})
+	if err != nil || resp.GetFilesSkipped() != 1 {
+		t.Fatalf("Skip failed: %v, %v", resp, err)
+	}
+	if _, err := os.Lstat(out + "/skipped.txt"); !os.IsNotExist(err) {
+		t.Errorf("Skipped link was copied: %v", err)
+	}
+}
+
+func TestCopySpecialFile(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t)
+	os.MkdirAll(out, 0755)
+	if err := unix.Mkfifo(in+"/fifo", 0644); err != nil {
+		t.Fatalf("Unable to make fifo: %v", err)
+	}
+
+	// Nothing ever writes to the fifo, so reading it would hang the copy
+	done := make(chan error, 1)
+	var resp *pb.CopyResponse
+	go func() {
+		var err error
+		resp, err = s.Copy(context.Background(), &pb.CopyRequest{InputFile: in + "/fifo", OutputFile: out + "/fifo", Transport: pb.TransportType_LOCAL})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil || resp.GetFilesSkipped() != 1 || resp.GetStatus() != pb.CopyStatus_SKIPPED {
+			t.Errorf("Fifo was not skipped: %v, %v", resp, err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatalf("Copy of a fifo never finished")
+	}
+	if _, err := os.Lstat(out + "/fifo"); !os.IsNotExist(err) {
+		t.Errorf("Fifo was copied: %v", err)
+	}
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: in + "/fifo", OutputFile: out + "/failed", Transport: pb.TransportType_LOCAL, SpecialFiles: pb.SpecialFilePolicy_FAIL_ON_SPECIAL_FILES})
+	if status.Convert(err).Code() != codes.FailedPrecondition {
+		t.Errorf("Fifo did not fail the copy: %v", err)
+	}
+}
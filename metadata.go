@@ -0,0 +1,96 @@
+package main
+
+import (
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	aclAccess  = "system.posix_acl_access"
+	aclDefault = "system.posix_acl_default"
+)
+
+func validateMetadata(policy *pb.MetadataPolicy) error {
+	if policy.GetSet().GetMode()&^07777 != 0 {
+		return status.Errorf(codes.InvalidArgument, "Bad mode %o, only permission bits can be set", policy.GetSet().GetMode())
+	}
+	for name := range policy.GetSet().GetXattrs() {
+		if name == aclAccess || name == aclDefault {
+			return status.Errorf(codes.InvalidArgument, "ACLs are set through access_acl and default_acl, not %v", name)
+		}
+	}
+	return nil
+}
+
+func preserves(policy *pb.MetadataPolicy) bool {
+	return policy.GetPreserveMode() || policy.GetPreserveOwner() || policy.GetPreserveGroup() ||
+		policy.GetPreserveMtime() || policy.GetPreserveAtime() || policy.GetPreserveXattrs() || policy.GetPreserveAcls()
+}
+
+// mergeMetadata puts the explicit values over the metadata read from the source
+func mergeMetadata(md, set *pb.FileMetadata) {
+	if set.GetHasMode() {
+		md.HasMode = true
+		md.Mode = set.GetMode()
+	}
+	if len(set.GetOwner()) > 0 {
+		md.Owner = set.GetOwner()
+	}
+	if len(set.GetGroup()) > 0 {
+		md.Group = set.GetGroup()
+	}
+	if set.GetMtime() != 0 {
+		md.Mtime = set.GetMtime()
+	}
+	if set.GetAtime() != 0 {
+		md.Atime = set.GetAtime()
+	}
+	for name, value := range set.GetXattrs() {
+		if md.Xattrs == nil {
+			md.Xattrs = make(map[string][]byte)
+		}
+		md.Xattrs[name] = value
+	}
+	if len(set.GetAccessAcl()) > 0 {
+		md.AccessAcl = set.GetAccessAcl()
+	}
+	if len(set.GetDefaultAcl()) > 0 {
+		md.DefaultAcl = set.GetDefaultAcl()
+	}
+}
+
+// sourceMetadata works out the metadata a copy should end up with, nil if it
+// has no policy. It's read before the copy, which could touch the access time.
+func (s *Server) sourceMetadata(ctx context.Context, in *pb.CopyRequest) (*pb.FileMetadata, error) {
+	policy := in.GetMetadata()
+	if policy == nil {
+		return nil, nil
+	}
+
+	md := &pb.FileMetadata{}
+	if preserves(policy) {
+		source, err := s.fs.getMetadata(ctx, in.GetInputServer(), in.GetInputFile(), policy)
+		if err != nil {
+			return nil, status.Errorf(status.Convert(err).Code(), "Unable to read metadata of %v: %v", in.GetInputFile(), err)
+		}
+		md = source
+	}
+	mergeMetadata(md, policy.GetSet())
+	return md, nil
+}
+
+// applyMetadata sets the metadata of the file a copy wrote to
+func (s *Server) applyMetadata(ctx context.Context, out *pb.CopyRequest, md *pb.FileMetadata, resp *pb.CopyResponse) error {
+	if md == nil {
+		return nil
+	}
+
+	applied, err := s.fs.setMetadata(ctx, out.GetOutputServer(), out.GetOutputFile(), md)
+	if err != nil {
+		return status.Errorf(status.Convert(err).Code(), "Unable to set metadata of %v: %v", out.GetOutputFile(), err)
+	}
+	resp.Metadata = applied
+	return nil
+}
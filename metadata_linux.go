@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/user"
+	"strconv"
+	"strings"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func metadataError(err error, format string, args ...interface{}) error {
+	code := classifyFile(err)
+	if errors.Is(err, unix.ENOTSUP) {
+		code = codes.FailedPrecondition
+	}
+	return status.Errorf(code, "%v: %v", fmt.Sprintf(format, args...), err)
+}
+
+// getXattr reads an extended attribute, nil if the file doesn't have it
+func getXattr(path, name string) ([]byte, error) {
+	for {
+		size, err := unix.Getxattr(path, name, nil)
+		if errors.Is(err, unix.ENODATA) || errors.Is(err, unix.ENOTSUP) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		value := make([]byte, size)
+		size, err = unix.Getxattr(path, name, value)
+		// The attribute may have grown since we sized it
+		if errors.Is(err, unix.ERANGE) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return value[:size], nil
+	}
+}
+
+// readXattrs reads all the extended attributes of a file, other than the ACLs
+func readXattrs(path string) (map[string][]byte, error) {
+	size, err := unix.Listxattr(path, nil)
+	if errors.Is(err, unix.ENOTSUP) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	list := make([]byte, size)
+	size, err = unix.Listxattr(path, list)
+	if err != nil {
+		return nil, err
+	}
+
+	xattrs := make(map[string][]byte)
+	for _, name := range strings.Split(string(list[:size]), "\x00") {
+		if len(name) == 0 || name == aclAccess || name == aclDefault {
+			continue
+		}
+		value, err := getXattr(path, name)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			xattrs[name] = value
+		}
+	}
+	return xattrs, nil
+}
+
+// readMetadata reads the metadata of a file that the policy preserves. Owners are
+// read as names where they can be, so they map over to the same user elsewhere.
+func readMetadata(path string, policy *pb.MetadataPolicy) (*pb.FileMetadata, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return nil, metadataError(err, "Unable to read metadata of %v", path)
+	}
+
+	md := &pb.FileMetadata{}
+	if policy.GetPreserveMode() {
+		md.HasMode = true
+		md.Mode = st.Mode & 07777
+	}
+	if policy.GetPreserveOwner() {
+		md.Owner = strconv.Itoa(int(st.Uid))
+		if u, err := user.LookupId(md.Owner); err == nil {
+			md.Owner = u.Username
+		}
+	}
+	if policy.GetPreserveGroup() {
+		md.Group = strconv.Itoa(int(st.Gid))
+		if g, err := user.LookupGroupId(md.Group); err == nil {
+			md.Group = g.Name
+		}
+	}
+	if policy.GetPreserveMtime() {
+		md.Mtime = st.Mtim.Nano()
+	}
+	if policy.GetPreserveAtime() {
+		md.Atime = st.Atim.Nano()
+	}
+
+	var err error
+	if policy.GetPreserveXattrs() {
+		if md.Xattrs, err = readXattrs(path); err != nil {
+			return nil, metadataError(err, "Unable to read extended attributes of %v", path)
+		}
+	}
+	if policy.GetPreserveAcls() {
+		if md.AccessAcl, err = getXattr(path, aclAccess); err != nil {
+			return nil, metadataError(err, "Unable to read the ACL of %v", path)
+		}
+		if md.DefaultAcl, err = getXattr(path, aclDefault); err != nil {
+			return nil, metadataError(err, "Unable to read the default ACL of %v", path)
+		}
+	}
+	return md, nil
+}
+
+// lookupID turns a user or group into its id on this server
+func lookupID(name string, lookup func(string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(name); err == nil {
+		return id, nil
+	}
+	id, err := lookup(name)
+	if err != nil {
+		return -1, status.Errorf(codes.FailedPrecondition, "Unable to find %v: %v", name, err)
+	}
+	return strconv.Atoi(id)
+}
+
+// writeMetadata sets whatever metadata is given on a file, returning what was
+// set with any owner and group as the ids they were set to
+func writeMetadata(path string, md *pb.FileMetadata) (*pb.FileMetadata, error) {
+	applied := &pb.FileMetadata{
+		HasMode:    md.GetHasMode(),
+		Mode:       md.GetMode(),
+		Mtime:      md.GetMtime(),
+		Atime:      md.GetAtime(),
+		Xattrs:     md.GetXattrs(),
+		AccessAcl:  md.GetAccessAcl(),
+		DefaultAcl: md.GetDefaultAcl(),
+	}
+
+	// Changing the owner can clear setuid and setgid, so it goes first
+	uid, gid := -1, -1
+	var err error
+	if len(md.GetOwner()) > 0 {
+		uid, err = lookupID(md.GetOwner(), func(name string) (string, error) {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return "", err
+			}
+			return u.Uid, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		applied.Owner = strconv.Itoa(uid)
+	}
+	if len(md.GetGroup()) > 0 {
+		gid, err = lookupID(md.GetGroup(), func(name string) (string, error) {
+			g, err := user.LookupGroup(name)
+			if err != nil {
+				return "", err
+			}
+			return g.Gid, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		applied.Group = strconv.Itoa(gid)
+	}
+	if uid != -1 || gid != -1 {
+		if err := unix.Chown(path, uid, gid); err != nil {
+			return nil, metadataError(err, "Unable to change the owner of %v", path)
+		}
+	}
+
+	for name, value := range md.GetXattrs() {
+		if err := unix.Setxattr(path, name, value, 0); err != nil {
+			return nil, metadataError(err, "Unable to set %v on %v", name, path)
+		}
+	}
+	if len(md.GetAccessAcl()) > 0 {
+		if err := unix.Setxattr(path, aclAccess, md.GetAccessAcl(), 0); err != nil {
+			return nil, metadataError(err, "Unable to set the ACL of %v", path)
+		}
+	}
+	if len(md.GetDefaultAcl()) > 0 {
+		if err := unix.Setxattr(path, aclDefault, md.GetDefaultAcl(), 0); err != nil {
+			return nil, metadataError(err, "Unable to set the default ACL of %v", path)
+		}
+	}
+
+	// The mode goes after the ACL, which would otherwise reset the group bits
+	if md.GetHasMode() {
+		if err := unix.Chmod(path, md.GetMode()); err != nil {
+			return nil, metadataError(err, "Unable to change the mode of %v", path)
+		}
+	}
+
+	if md.GetMtime() != 0 || md.GetAtime() != 0 {
+		times := []unix.Timespec{{Nsec: unix.UTIME_OMIT}, {Nsec: unix.UTIME_OMIT}}
+		if md.GetAtime() != 0 {
+			times[0] = unix.NsecToTimespec(md.GetAtime())
+		}
+		if md.GetMtime() != 0 {
+			times[1] = unix.NsecToTimespec(md.GetMtime())
+		}
+		if err := unix.UtimesNanoAt(unix.AT_FDCWD, path, times, 0); err != nil {
+			return nil, metadataError(err, "Unable to set the times of %v", path)
+		}
+	}
+	return applied, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCopyPreservesMetadata(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "a.txt")
+	os.MkdirAll(out, 0755)
+
+	stamp := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+	os.Chmod(in+"/a.txt", 0640)
+	os.Chtimes(in+"/a.txt", stamp.Add(time.Hour), stamp)
+	xattrs := unix.Setxattr(in+"/a.txt", "user.filecopier", []byte("value"), 0) == nil
+
+	policy := &pb.MetadataPolicy{PreserveMode: true, PreserveMtime: true, PreserveAtime: true, PreserveOwner: true, PreserveXattrs: true, PreserveAcls: true}
+	resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: in + "/a.txt", OutputFile: out + "/a.txt", Transport: pb.TransportType_LOCAL, Metadata: policy})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+
+	var st unix.Stat_t
+	unix.Stat(out+"/a.txt", &st)
+	if st.Mode&07777 != 0640 || st.Mtim.Nano() != stamp.UnixNano() || st.Atim.Nano() != stamp.Add(time.Hour).UnixNano() {
+		t.Errorf("Metadata was not preserved: %o, %v, %v", st.Mode&07777, st.Mtim.Nano(), st.Atim.Nano())
+	}
+	if resp.GetMetadata().GetOwner() != strconv.Itoa(os.Getuid()) || resp.GetMetadata().GetMode() != 0640 {
+		t.Errorf("Bad applied metadata: %v", resp.GetMetadata())
+	}
+	if xattrs {
+		if value, _ := getXattr(out+"/a.txt", "user.filecopier"); string(value) != "value" {
+			t.Errorf("Extended attribute was not copied: %q", value)
+		}
+	}
+}
+
+func TestCopySetsMetadata(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "a.txt")
+	os.MkdirAll(out, 0755)
+	os.Chmod(in+"/a.txt", 0644)
+
+	stamp := time.Date(2021, 6, 7, 8, 9, 10, 0, time.UTC)
+	policy := &pb.MetadataPolicy{PreserveMode: true, Set: &pb.FileMetadata{HasMode: true, Mode: 0600, Mtime: stamp.UnixNano(), Group: strconv.Itoa(os.Getgid())}}
+	for _, transport := range []pb.TransportType{pb.TransportType_LOCAL, pb.TransportType_GRPC_STREAM} {
+		os.Remove(out + "/a.txt")
+		resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: in + "/a.txt", OutputFile: out + "/a.txt", Transport: transport, Metadata: policy})
+		if err != nil {
+			t.Fatalf("Copy over %v failed: %v", transport, err)
+		}
+
+		info, err := os.Stat(out + "/a.txt")
+		if err != nil || info.Mode().Perm() != 0600 || !info.ModTime().Equal(stamp) {
+			t.Errorf("Metadata was not set over %v: %v, %v", transport, info, err)
+		}
+		if resp.GetMetadata().GetGroup() != strconv.Itoa(os.Getgid()) {
+			t.Errorf("Bad applied metadata over %v: %v", transport, resp.GetMetadata())
+		}
+	}
+}
+
+func TestCopyUnknownOwner(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "a.txt")
+	os.MkdirAll(out, 0755)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: in + "/a.txt", OutputFile: out + "/a.txt", Transport: pb.TransportType_LOCAL, Metadata: &pb.MetadataPolicy{Set: &pb.FileMetadata{Owner: "no-such-user-here"}}})
+	if status.Convert(err).Code() != codes.FailedPrecondition {
+		t.Errorf("Unknown owner did not fail: %v", err)
+	}
+	if _, err := os.Stat(out + "/a.txt"); !os.IsNotExist(err) {
+		t.Errorf("Copy was left in place: %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package main
+
+import (
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// readMetadata reads the metadata of a file, which we only know how to do on linux
+func readMetadata(path string, policy *pb.MetadataPolicy) (*pb.FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "Unable to read metadata of %v on this platform", path)
+}
+
+// writeMetadata sets the metadata of a file, which we only know how to do on linux
+func writeMetadata(path string, md *pb.FileMetadata) (*pb.FileMetadata, error) {
+	return nil, status.Errorf(codes.Unimplemented, "Unable to set metadata of %v on this platform", path)
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCopyBadMetadata(t *testing.T) {
+	s := InitTestServer()
+	_, err := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "a.txt", OutputFile: "b.txt", Metadata: &pb.MetadataPolicy{Set: &pb.FileMetadata{HasMode: true, Mode: 0100644}}})
+	if status.Convert(err).Code() != codes.InvalidArgument {
+		t.Errorf("Bad mode was accepted: %v", err)
+	}
+}
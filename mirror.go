@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func entryKey(e *pb.FileEntry) string {
+	return fmt.Sprintf("%v:%v", e.GetIsDir(), e.GetPath())
+}
+
+// within is true if the path is the directory or somewhere under it
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// listDestination lists what's already where a directory copy is going, nothing if it isn't there yet.
+// Links are never followed, a mirror mustn't reach outside the destination.
+func (s *Server) listDestination(ctx context.Context, in *pb.CopyRequest) ([]*pb.FileEntry, error) {
+	dest, err := s.fs.list(ctx, in.GetOutputServer(), &pb.ListDirRequest{
+		Path:     in.GetOutputFile(),
+		Include:  in.GetInclude(),
+		Exclude:  in.GetExclude(),
+		Symlinks: pb.SymlinkPolicy_COPY_SYMLINKS,
+	})
+	if status.Convert(err).Code() == codes.NotFound {
+		return nil, nil
+	}
+	return dest.GetEntries(), err
+}
+
+// planMirror works out what a mirrored copy is going to do to the destination
+func planMirror(job *pb.DirJob, dest []*pb.FileEntry) {
+	files := append(append([]*pb.FileEntry{}, job.Pending...), job.Links...)
+	source := make(map[string]bool)
+	for _, e := range append(append([]*pb.FileEntry{}, job.Dirs...), files...) {
+		source[entryKey(e)] = true
+	}
+
+	existing := make(map[string]bool)
+	var extraneous, dirs []*pb.FileEntry
+	for _, e := range dest {
+		existing[entryKey(e)] = true
+		switch {
+		case source[entryKey(e)]:
+		case e.GetIsDir():
+			dirs = append(dirs, e)
+		default:
+			extraneous = append(extraneous, e)
+		}
+	}
+
+	// A directory has to be emptied before it can go, and it's listed before what's in it
+	for i := len(dirs) - 1; i >= 0; i-- {
+		extraneous = append(extraneous, dirs[i])
+	}
+	job.Extraneous = extraneous
+
+	for _, e := range files {
+		if existing[entryKey(e)] {
+			job.FilesUpdated++
+		} else {
+			job.FilesAdded++
+		}
+	}
+}
+
+// discard removes something from the destination of a mirrored copy, or moves it to the trash
+func (s *Server) discard(ctx context.Context, in *pb.CopyRequest, e *pb.FileEntry) error {
+	path := filepath.Join(in.GetOutputFile(), e.GetPath())
+	if len(in.GetTrashDir()) == 0 || e.GetIsDir() {
+		return s.fs.remove(ctx, in.GetOutputServer(), path)
+	}
+
+	trash := filepath.Join(in.GetTrashDir(), e.GetPath())
+	if err := s.fs.makeDir(ctx, in.GetOutputServer(), filepath.Dir(trash), 0); err != nil {
+		return err
+	}
+	return s.fs.rename(ctx, in.GetOutputServer(), path, trash)
+}
+
+// removeExtraneous clears out what a mirrored copy doesn't want at the
+// destination, it's false if that has to be tried again later
+func (s *Server) removeExtraneous(ctx context.Context, job *pb.DirJob) bool {
+	for len(job.Extraneous) > 0 {
+		e := job.Extraneous[0]
+		err := s.discard(ctx, job.GetReq(), e)
+		switch {
+		case status.Convert(err).Code() == codes.Unavailable:
+			job.Error = fmt.Sprintf("%v", err)
+			return false
+		case err != nil && e.GetIsDir():
+			// The directory may still hold things we were told to leave alone
+			s.CtxLog(ctx, fmt.Sprintf("Leaving %v in place: %v", e.GetPath(), err))
+		case err != nil:
+			job.Error = fmt.Sprintf("%v", err)
+			job.FilesFailed++
+		case !e.GetIsDir():
+			job.FilesDeleted++
+		}
+		job.Extraneous = job.Extraneous[1:]
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func writeFiles(dir string, files ...string) {
+	for _, f := range files {
+		os.MkdirAll(filepath.Dir(dir+"/"+f), 0755)
+		ioutil.WriteFile(dir+"/"+f, []byte("old "+f), 0644)
+	}
+}
+
+func runJobs(s *Server) {
+	s.jobs.interval = time.Millisecond * 10
+	go s.runQueue()
+	go s.runJobs()
+}
+
+func TestMirror(t *testing.T) {
+	s := InitTestServer()
+	runJobs(s)
+	in, out := makeTree(t, "keep.txt", "new.txt", "sub/a.txt")
+	writeFiles(out, "keep.txt", "stale.txt", "old/x.txt", "old/deeper/y.txt", ".git/config")
+
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, Mirror: true, Exclude: []string{".git/"}})
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if resp.GetFilesAdded() != 2 || resp.GetFilesUpdated() != 1 || resp.GetFilesDeleted() != 3 {
+		t.Errorf("Bad plan: %v", resp)
+	}
+
+	job := waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+	if job.GetFilesDeleted() != 3 || job.GetFilesComplete() != 3 {
+		t.Errorf("Bad job: %v", job)
+	}
+
+	for _, gone := range []string{"stale.txt", "old"} {
+		if _, err := os.Stat(out + "/" + gone); !os.IsNotExist(err) {
+			t.Errorf("%v was not removed: %v", gone, err)
+		}
+	}
+	if _, err := os.Stat(out + "/.git/config"); err != nil {
+		t.Errorf("Excluded file was removed: %v", err)
+	}
+	if data, _ := ioutil.ReadFile(out + "/keep.txt"); string(data) != "contents of keep.txt" {
+		t.Errorf("File was not updated: %v", string(data))
+	}
+}
+
+func TestMirrorToTrash(t *testing.T) {
+	s := InitTestServer()
+	runJobs(s)
+	in, out := makeTree(t, "keep.txt")
+	writeFiles(out, "stale.txt", "old/x.txt")
+	trash := filepath.Dir(out) + "/trash"
+
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, Mirror: true, TrashDir: trash})
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+
+	for _, f := range []string{"stale.txt", "old/x.txt"} {
+		if data, err := ioutil.ReadFile(trash + "/" + f); err != nil || string(data) != "old "+f {
+			t.Errorf("%v was not moved to the trash: %v", f, err)
+		}
+	}
+	if _, err := os.Stat(out + "/old"); !os.IsNotExist(err) {
+		t.Errorf("Emptied directory was left behind: %v", err)
+	}
+}
+
+func TestMirrorReplacesFileWithDirectory(t *testing.T) {
+	s := InitTestServer()
+	runJobs(s)
+	in, out := makeTree(t, "sub/a.txt")
+	writeFiles(out, "sub")
+
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, Mirror: true})
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+
+	if data, err := ioutil.ReadFile(out + "/sub/a.txt"); err != nil || string(data) != "contents of sub/a.txt" {
+		t.Errorf("Directory did not replace the file: %v", err)
+	}
+}
+
+func TestMirrorPlanning(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "one.txt", "two.txt")
+
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Mirror: true})
+	if err != nil || resp.GetFilesAdded() != 2 || resp.GetFilesDeleted() != 0 {
+		t.Errorf("Bad mirror to a new directory: %v, %v", resp, err)
+	}
+
+	_, err = s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Mirror: true, TrashDir: out + "/.trash"})
+	if status.Convert(err).Code() != codes.InvalidArgument {
+		t.Errorf("Trash inside the mirror was accepted: %v", err)
+	}
+}
+
+func TestMirrorAgain(t *testing.T) {
+	s := InitTestServer()
+	runJobs(s)
+	in, out := makeTree(t, "config.txt", "other.txt")
+
+	resp, _ := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, Mirror: true})
+	waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+
+	ioutil.WriteFile(in+"/config.txt", []byte("new config"), 0644)
+	os.Remove(in + "/other.txt")
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Transport: pb.TransportType_LOCAL, Mirror: true})
+	if err != nil {
+		t.Fatalf("Second mirror failed: %v", err)
+	}
+	if resp.GetFilesUpdated() != 1 || resp.GetFilesDeleted() != 1 {
+		t.Errorf("Bad plan: %v", resp)
+	}
+
+	job := waitForJob(t, s, resp.GetId(), pb.CopyStatus_COMPLETE)
+	if job.GetFilesComplete() != 1 || job.GetFilesDeleted() != 1 {
+		t.Errorf("Bad job: %v", job)
+	}
+	if data, _ := ioutil.ReadFile(out + "/config.txt"); string(data) != "new config" {
+		t.Errorf("Changed file was not copied: %q", data)
+	}
+	if _, err := os.Stat(out + "/other.txt"); !os.IsNotExist(err) {
+		t.Errorf("Removed file was left behind: %v", err)
+	}
+}
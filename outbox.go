@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"github.com/brotherlogic/goserver/utils"
+	"golang.org/x/net/context"
+	"google.golang.org/protobuf/proto"
+)
+
+// callbackBackoff is how long we wait between callback attempts, there's no
+// limit on the number of attempts - we keep going until the callback gets through
+var callbackBackoff = &pb.RetryPolicy{
+	InitialBackoffMs: 1000,
+	MaxBackoffMs:     10 * 60 * 1000,
+	Jitter:           0.2,
+}
+
+const (
+	// callbackLease stops a callback being sent twice at once, it's longer
+	// than we give any single attempt
+	callbackLease = time.Minute * 2
+
+	// deliveredRetention is how long we remember delivered callbacks so their
+	// state can be reported
+	deliveredRetention = time.Hour * 24
+)
+
+// outbox holds callbacks on disk until the caller has acknowledged them
+type outbox struct {
+	file       string
+	mutex      sync.Mutex
+	deliveries []*pb.CallbackDelivery
+	backoff    *pb.RetryPolicy
+	wakeup     chan struct{}
+}
+
+func newOutbox(file string) *outbox {
+	return &outbox{file: file, backoff: callbackBackoff, wakeup: make(chan struct{}, 1)}
+}
+
+func (o *outbox) load() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	data, err := ioutil.ReadFile(o.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	list := &pb.CallbackOutbox{}
+	if err := proto.Unmarshal(data, list); err != nil {
+		return err
+	}
+	o.deliveries = list.GetDeliveries()
+	return nil
+}
+
+// save writes out the outbox, dropping anything delivered long enough ago, call with the lock held
+func (o *outbox) save() error {
+	var deliveries []*pb.CallbackDelivery
+	for _, d := range o.deliveries {
+		if d.GetState() != pb.CallbackState_CALLBACK_DELIVERED || time.Since(fromUnixNano(d.GetDeliveredTime())) < deliveredRetention {
+			deliveries = append(deliveries, d)
+		}
+	}
+	o.deliveries = deliveries
+
+	data, err := proto.Marshal(&pb.CallbackOutbox{Deliveries: o.deliveries})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(o.file, data)
+}
+
+// add puts a callback in the outbox, leased to the caller for the first attempt.
+// Every callback gets an id so that the receiver can spot repeat deliveries.
+func (o *outbox) add(server string, req *pb.CallbackRequest) (*pb.CallbackDelivery, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	req = proto.Clone(req).(*pb.CallbackRequest)
+	if len(req.GetId()) == 0 {
+		req.Id = newID()
+	}
+
+	d := &pb.CallbackDelivery{
+		Server:          server,
+		Request:         req,
+		State:           pb.CallbackState_CALLBACK_PENDING,
+		NextAttemptTime: time.Now().Add(callbackLease).UnixNano(),
+	}
+
+	var deliveries []*pb.CallbackDelivery
+	for _, e := range o.deliveries {
+		if e.GetRequest().GetId() != req.GetId() {
+			deliveries = append(deliveries, e)
+		}
+	}
+	o.deliveries = append(deliveries, d)
+	return proto.Clone(d).(*pb.CallbackDelivery), o.save()
+}
+
+// take leases out the callbacks which are due another attempt
+func (o *outbox) take(now time.Time) ([]*pb.CallbackDelivery, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	var due []*pb.CallbackDelivery
+	for _, d := range o.deliveries {
+		if d.GetState() == pb.CallbackState_CALLBACK_PENDING && d.GetNextAttemptTime() <= now.UnixNano() {
+			d.NextAttemptTime = now.Add(callbackLease).UnixNano()
+			due = append(due, proto.Clone(d).(*pb.CallbackDelivery))
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	return due, o.save()
+}
+
+// result records how an attempt at a callback went
+func (o *outbox) result(id string, err error) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, d := range o.deliveries {
+		if d.GetRequest().GetId() == id {
+			d.Attempts++
+			if err == nil {
+				d.State = pb.CallbackState_CALLBACK_DELIVERED
+				d.DeliveredTime = time.Now().UnixNano()
+				d.NextAttemptTime = 0
+				d.LastError = ""
+			} else {
+				d.LastError = fmt.Sprintf("%v", err)
+				d.NextAttemptTime = time.Now().Add(backoff(o.backoff, d.GetAttempts())).UnixNano()
+			}
+			return o.save()
+		}
+	}
+	return nil
+}
+
+func (o *outbox) get(id string) *pb.CallbackDelivery {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, d := range o.deliveries {
+		if d.GetRequest().GetId() == id {
+			return proto.Clone(d).(*pb.CallbackDelivery)
+		}
+	}
+	return nil
+}
+
+// next is when the next pending callback is due, zero if there's nothing to send
+func (o *outbox) next() time.Time {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	var next time.Time
+	for _, d := range o.deliveries {
+		t := fromUnixNano(d.GetNextAttemptTime())
+		if d.GetState() == pb.CallbackState_CALLBACK_PENDING && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+func (o *outbox) wake() {
+	select {
+	case o.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// deliver makes a single attempt at a callback
+func (s *Server) deliver(d *pb.CallbackDelivery) {
+	// The copy's own context may have been cancelled or run out
+	ctx, cancel := utils.ManualContext("fc-callback", time.Minute)
+	defer cancel()
+
+	s.CtxLog(ctx, fmt.Sprintf("Calling back %v with %v", d.GetServer(), d.GetRequest()))
+	err := s.callbacker.callback(ctx, d.GetServer(), d.GetRequest())
+	if err != nil {
+		s.CtxLog(ctx, fmt.Sprintf("Callback to %v failed (attempt %v): %v", d.GetServer(), d.GetAttempts()+1, err))
+	}
+	if err := s.outbox.result(d.GetRequest().GetId(), err); err != nil {
+		s.CtxLog(ctx, fmt.Sprintf("Unable to record callback %v: %v", d.GetRequest().GetId(), err))
+	}
+}
+
+// deliverCallbacks has another go at everything in the outbox that's due
+func (s *Server) deliverCallbacks() {
+	due, err := s.outbox.take(time.Now())
+	if err != nil {
+		s.CtxLog(context.Background(), fmt.Sprintf("Unable to lease callbacks: %v", err))
+	}
+	for _, d := range due {
+		s.deliver(d)
+	}
+}
+
+// runCallbacks keeps retrying callbacks until they get through
+func (s *Server) runCallbacks() {
+	for {
+		s.deliverCallbacks()
+
+		wait := time.Minute
+		if next := s.outbox.next(); !next.IsZero() && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		select {
+		case <-s.outbox.wakeup:
+		case <-time.After(wait):
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+)
+
+func addProblem(plan *pb.Plan, format string, args ...interface{}) {
+	plan.Problems = append(plan.Problems, fmt.Sprintf(format, args...))
+}
+
+func addServer(plan *pb.Plan, server string) {
+	for _, s := range plan.GetServers() {
+		if s == server {
+			return
+		}
+	}
+	plan.Servers = append(plan.Servers, server)
+}
+
+// checkServers makes sure the copy can be run between the servers at all
+func (s *Server) checkServers(ctx context.Context, in *pb.CopyRequest, plan *pb.Plan) {
+	tr, err := s.getTransport(ctx, in)
+	if err != nil {
+		addProblem(plan, "%v", err)
+		return
+	}
+
+	if tr.sshKeys() {
+		for _, server := range []string{in.GetInputServer(), in.GetOutputServer()} {
+			if err := s.checker.check(ctx, server); err != nil {
+				addProblem(plan, "%v is unable to handle this request: %v", server, err)
+			}
+		}
+	}
+}
+
+// checkDestination makes sure there's room to write to the output, returning true if it's already there
+func (s *Server) checkDestination(ctx context.Context, server, path string, size int64, plan *pb.Plan) bool {
+	dest, err := s.fs.stat(ctx, server, path)
+	if err != nil {
+		addProblem(plan, "Unable to check %v on %v: %v", path, server, err)
+		return false
+	}
+
+	if !dest.GetWritable() {
+		addProblem(plan, "Unable to write to %v on %v", path, server)
+	}
+	if dest.GetFreeBytes() >= 0 && dest.GetFreeBytes() < size {
+		addProblem(plan, "Not enough room for %v on %v: need %v bytes but only %v are free", path, server, size, dest.GetFreeBytes())
+	}
+	return dest.GetExists()
+}
+
+// planCopy runs the checks for a single copy and adds it to the plan, without copying anything
+func (s *Server) planCopy(ctx context.Context, in *pb.CopyRequest, plan *pb.Plan) {
+	addServer(plan, in.GetOutputServer())
+	s.checkServers(ctx, in, plan)
+
+	op := &pb.PlannedOperation{
+		Action:       pb.PlannedOperation_COPY,
+		InputServer:  in.GetInputServer(),
+		InputFile:    in.GetInputFile(),
+		OutputServer: in.GetOutputServer(),
+		OutputFile:   in.GetOutputFile(),
+	}
+
+	source, err := s.fs.stat(ctx, in.GetInputServer(), in.GetInputFile())
+	switch {
+	case err != nil:
+		addProblem(plan, "Unable to check %v on %v: %v", in.GetInputFile(), in.GetInputServer(), err)
+	case !source.GetExists():
+		addProblem(plan, "%v does not exist on %v", in.GetInputFile(), in.GetInputServer())
+	case source.GetIsDir():
+		addProblem(plan, "%v on %v is a directory", in.GetInputFile(), in.GetInputServer())
+	default:
+		op.Size = source.GetSize()
+	}
+
+	op.Overwrites = s.checkDestination(ctx, in.GetOutputServer(), in.GetOutputFile(), op.GetSize(), plan)
+	plan.Operations = append(plan.Operations, op)
+	plan.Bytes += op.GetSize()
+}
+
+// planDirCopy lays out everything a directory copy would do, given what's at the destination
+func (s *Server) planDirCopy(ctx context.Context, job *pb.DirJob, dest []*pb.FileEntry) *pb.Plan {
+	in := job.GetReq()
+	plan := &pb.Plan{}
+	addServer(plan, in.GetOutputServer())
+	s.checkServers(ctx, in, plan)
+
+	for _, e := range job.GetExtraneous() {
+		op := &pb.PlannedOperation{Action: pb.PlannedOperation_REMOVE, OutputServer: in.GetOutputServer(), OutputFile: filepath.Join(in.GetOutputFile(), e.GetPath()), Size: e.GetSize()}
+		if len(in.GetTrashDir()) > 0 && !e.GetIsDir() {
+			op.Action = pb.PlannedOperation_TRASH
+			op.InputServer = in.GetOutputServer()
+			op.InputFile = op.OutputFile
+			op.OutputFile = filepath.Join(in.GetTrashDir(), e.GetPath())
+		}
+		plan.Operations = append(plan.Operations, op)
+	}
+
+	for _, d := range job.GetDirs() {
+		plan.Operations = append(plan.Operations, &pb.PlannedOperation{Action: pb.PlannedOperation_MAKE_DIR, OutputServer: in.GetOutputServer(), OutputFile: filepath.Join(in.GetOutputFile(), d.GetPath())})
+	}
+
+	existing := make(map[string]bool)
+	for _, e := range dest {
+		existing[entryKey(e)] = true
+	}
+	for _, f := range job.GetPending() {
+		child := childRequest(in, f)
+		plan.Operations = append(plan.Operations, &pb.PlannedOperation{
+			Action:       pb.PlannedOperation_COPY,
+			InputServer:  child.GetInputServer(),
+			InputFile:    child.GetInputFile(),
+			OutputServer: child.GetOutputServer(),
+			OutputFile:   child.GetOutputFile(),
+			Size:         f.GetSize(),
+			Overwrites:   existing[entryKey(f)],
+		})
+		plan.Bytes += f.GetSize()
+	}
+	for _, l := range job.GetLinks() {
+		op := &pb.PlannedOperation{
+			Action:       pb.PlannedOperation_SYMLINK,
+			InputFile:    l.GetSymlink(),
+			OutputServer: in.GetOutputServer(),
+			OutputFile:   filepath.Join(in.GetOutputFile(), l.GetPath()),
+			Overwrites:   existing[entryKey(l)],
+		}
+		if len(l.GetHardlink()) > 0 {
+			op.Action = pb.PlannedOperation_LINK
+			op.InputServer = in.GetOutputServer()
+			op.InputFile = filepath.Join(in.GetOutputFile(), l.GetHardlink())
+		}
+		plan.Operations = append(plan.Operations, op)
+	}
+
+	s.checkDestination(ctx, in.GetOutputServer(), in.GetOutputFile(), plan.GetBytes(), plan)
+	return plan
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+)
+
+func TestCopyDryRun(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "a.txt")
+	os.MkdirAll(out, 0755)
+
+	resp, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: in + "/a.txt", OutputFile: out + "/a.txt", DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(resp.GetPlan().GetProblems()) > 0 || len(resp.GetPlan().GetOperations()) != 1 || resp.GetPlan().GetBytes() != int64(len("contents of a.txt")) {
+		t.Errorf("Bad plan: %v", resp.GetPlan())
+	}
+	if _, err := os.Stat(out + "/a.txt"); !os.IsNotExist(err) {
+		t.Errorf("Dry run copied the file: %v", err)
+	}
+}
+
+func TestCopyDryRunProblems(t *testing.T) {
+	s := InitTestServer()
+	s.checker = &testChecker{failServer: "output"}
+	in, out := makeTree(t)
+
+	resp, err := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: in + "/missing.txt", OutputFile: out + "/a.txt", OutputServer: "output", DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(resp.GetPlan().GetProblems()) != 2 {
+		t.Errorf("Expected the missing file and failed check to be reported: %v", resp.GetPlan())
+	}
+	if len(s.queue) != 0 {
+		t.Errorf("Dry run was queued: %v", s.queue)
+	}
+}
+
+func TestDirCopyDryRun(t *testing.T) {
+	s := InitTestServer()
+	in, out := makeTree(t, "keep.txt", "new.txt", "sub/a.txt")
+	writeFiles(out, "keep.txt", "stale.txt")
+	trash, _ := ioutil.TempDir("", "filecopier-trash")
+	defer os.RemoveAll(trash)
+
+	resp, err := s.DirCopy(context.Background(), &pb.CopyRequest{InputFile: in, OutputFile: out, Mirror: true, TrashDir: trash, DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if len(resp.GetId()) > 0 || resp.GetFilesAdded() != 2 || resp.GetFilesUpdated() != 1 || resp.GetFilesDeleted() != 1 {
+		t.Errorf("Bad response: %v", resp)
+	}
+
+	counts := make(map[pb.PlannedOperation_Action]int)
+	for _, op := range resp.GetPlan().GetOperations() {
+		counts[op.GetAction()]++
+		if op.GetAction() == pb.PlannedOperation_COPY && op.GetOutputFile() == out+"/keep.txt" && !op.GetOverwrites() {
+			t.Errorf("Copy over keep.txt not marked as an overwrite: %v", op)
+		}
+	}
+	if counts[pb.PlannedOperation_COPY] != 3 || counts[pb.PlannedOperation_TRASH] != 1 || counts[pb.PlannedOperation_MAKE_DIR] == 0 {
+		t.Errorf("Bad plan: %v", resp.GetPlan())
+	}
+
+	if _, err := os.Stat(out + "/stale.txt"); err != nil {
+		t.Errorf("Dry run removed a file: %v", err)
+	}
+	if _, err := os.Stat(out + "/new.txt"); !os.IsNotExist(err) {
+		t.Errorf("Dry run copied a file: %v", err)
+	}
+	if _, err := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: resp.GetId()}); err == nil {
+		t.Errorf("Dry run stored a job")
+	}
+}
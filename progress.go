@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+)
+
+// longCopyTime is how long a copy can take before we raise an issue about it
+const longCopyTime = time.Hour
+
+// progressInterval is how often running copies are checked for being too slow
+var progressInterval = time.Minute
+
+// trackTransfer makes a running transfer available for progress reports
+func (s *Server) trackTransfer(in *pb.CopyRequest, running transfer) {
+	s.ccopiesMutex.Lock()
+	defer s.ccopiesMutex.Unlock()
+	s.transfers[in] = running
+}
+
+func (s *Server) untrackTransfer(in *pb.CopyRequest) {
+	s.ccopiesMutex.Lock()
+	defer s.ccopiesMutex.Unlock()
+	delete(s.transfers, in)
+}
+
+// progress is how far the transfer for a request has got, zero if it isn't running
+func (s *Server) progress(in *pb.CopyRequest) (int64, int64) {
+	s.ccopiesMutex.Lock()
+	running, ok := s.transfers[in]
+	s.ccopiesMutex.Unlock()
+
+	if !ok {
+		return 0, 0
+	}
+	return running.progress()
+}
+
+// estimate works out how fast a copy is going and how long it has left, zero if we can't tell
+func estimate(done, total int64, elapsed time.Duration) (float64, time.Duration) {
+	if elapsed <= 0 || done <= 0 {
+		return 0, 0
+	}
+
+	rate := float64(done) / elapsed.Seconds()
+	if total <= done {
+		return rate, 0
+	}
+	return rate, time.Duration(float64(total-done) / rate * float64(time.Second))
+}
+
+// longCopy is true if a copy has run, or looks like it will run, for too long
+func longCopy(done, total int64, elapsed time.Duration) bool {
+	_, eta := estimate(done, total, elapsed)
+	return elapsed+eta > longCopyTime
+}
+
+// updateProgress brings a running entry's progress up to date, call with the queue lock held
+func (s *Server) updateProgress(entry *queueEntry) {
+	if entry.resp.GetStatus() != pb.CopyStatus_IN_PROGRESS {
+		return
+	}
+
+	done, total := s.progress(entry.req)
+	rate, eta := estimate(done, total, time.Since(entry.timeStarted))
+	entry.resp.BytesDone = done
+	entry.resp.BytesTotal = total
+	entry.resp.BytesPerSecond = rate
+	entry.resp.EtaMillis = eta.Milliseconds()
+}
+
+// clearProgress drops the progress of an entry which has stopped running, call with the queue lock held
+func (s *Server) clearProgress(entry *queueEntry) {
+	entry.resp.BytesDone = 0
+	entry.resp.BytesTotal = 0
+	entry.resp.BytesPerSecond = 0
+	entry.resp.EtaMillis = 0
+}
+
+// watchLongCopy raises an issue as soon as a copy looks like it's going to take
+// too long, rather than waiting for it to finish; call the returned func once the copy is done
+func (s *Server) watchLongCopy(in *pb.CopyRequest, running transfer, started time.Time) func() {
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			done, total := running.progress()
+			if elapsed := time.Since(started); longCopy(done, total, elapsed) {
+				_, eta := estimate(done, total, elapsed)
+				s.RaiseIssue("Long Copy Time", fmt.Sprintf("Copy from %v to %v has taken %v and has about %v to go (%v of %v bytes)",
+					in.GetInputServer(), in.GetOutputServer(), elapsed.Round(time.Second), eta.Round(time.Second), done, total))
+				return
+			}
+		}
+	}()
+	return func() { close(stop) }
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+)
+
+func TestEstimate(t *testing.T) {
+	rate, eta := estimate(100, 300, time.Second*2)
+	if rate != 50 || eta != time.Second*4 {
+		t.Errorf("Bad estimate: %v, %v", rate, eta)
+	}
+
+	if rate, eta := estimate(0, 300, time.Second); rate != 0 || eta != 0 {
+		t.Errorf("Estimated without any progress: %v, %v", rate, eta)
+	}
+	if rate, eta := estimate(100, 0, time.Second); rate != 100 || eta != 0 {
+		t.Errorf("Estimated without a total: %v, %v", rate, eta)
+	}
+}
+
+func TestLongCopy(t *testing.T) {
+	if longCopy(100, 200, time.Minute) {
+		t.Errorf("Short copy was too long")
+	}
+	if !longCopy(10, 1000, time.Minute) {
+		t.Errorf("Slow copy was not spotted")
+	}
+	if !longCopy(0, 0, time.Hour*2) {
+		t.Errorf("Copy which has run too long was not spotted")
+	}
+}
+
+func TestStatusShowsProgress(t *testing.T) {
+	s := InitTestServer()
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+
+	s.queueMutex.Lock()
+	entry := s.findEntry(queued.GetId(), 0)
+	entry.resp.Status = pb.CopyStatus_IN_PROGRESS
+	entry.timeStarted = time.Now().Add(-time.Second * 10)
+	s.trackTransfer(entry.req, &fixedTransfer{done: 1000, total: 4000})
+	s.queueMutex.Unlock()
+
+	resp, _ := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: queued.GetId()})
+	r := resp.GetEntry().GetResp()
+	if r.GetBytesDone() != 1000 || r.GetBytesTotal() != 4000 || r.GetBytesPerSecond() < 90 || r.GetEtaMillis() < 29000 {
+		t.Errorf("Bad progress: %v", r)
+	}
+
+	s.untrackTransfer(entry.req)
+	s.queueMutex.Lock()
+	s.clearProgress(entry)
+	entry.resp.Status = pb.CopyStatus_COMPLETE
+	s.queueMutex.Unlock()
+
+	resp, _ = s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: queued.GetId()})
+	if resp.GetEntry().GetResp().GetBytesDone() != 0 || resp.GetEntry().GetResp().GetEtaMillis() != 0 {
+		t.Errorf("Finished copy still has progress: %v", resp)
+	}
+}
+
+func TestCommandProgress(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "filecopier")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/in.txt", make([]byte, 300), 0644)
+
+	c := newCommand(context.Background(), "true")
+	c.follow(func(string) bool { return true }, &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+	if done, total := c.progress(); done != 0 || total != 300 {
+		t.Errorf("Bad progress before writing: %v/%v", done, total)
+	}
+
+	ioutil.WriteFile(dir+"/out.txt", make([]byte, 100), 0644)
+	if done, total := c.progress(); done != 100 || total != 300 {
+		t.Errorf("Bad progress: %v/%v", done, total)
+	}
+
+	// Remote files can't be followed
+	c = newCommand(context.Background(), "true")
+	c.follow(func(string) bool { return false }, &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+	if done, total := c.progress(); done != 0 || total != 0 {
+		t.Errorf("Followed a remote copy: %v/%v", done, total)
+	}
+}
@@ -1,17 +1,17 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.25.0
-// 	protoc        v3.6.1
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
 // source: filecopier.proto
 
 package proto
 
 import (
-	proto "github.com/golang/protobuf/proto"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
 	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -21,10 +21,6 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// This is a compile-time assertion that a sufficiently up-to-date version
-// of the legacy proto package is being used.
-const _ = proto.ProtoPackageIsVersion4
-
 type CopyStatus int32
 
 const (
@@ -32,6 +28,15 @@ const (
 	CopyStatus_IN_QUEUE    CopyStatus = 1
 	CopyStatus_IN_PROGRESS CopyStatus = 2
 	CopyStatus_COMPLETE    CopyStatus = 3
+	// The copy didn't match the source once it was done
+	CopyStatus_VERIFY_FAILED CopyStatus = 4
+	CopyStatus_CANCELLED     CopyStatus = 5
+	// The copy ran out of retries and is in the dead letter store
+	CopyStatus_DEAD_LETTERED CopyStatus = 6
+	// The copy, or some part of a directory copy, could not be done
+	CopyStatus_FAILED CopyStatus = 7
+	// The copy's condition found nothing needed copying
+	CopyStatus_SKIPPED CopyStatus = 8
 )
 
 // Enum value maps for CopyStatus.
@@ -41,12 +46,22 @@ var (
 		1: "IN_QUEUE",
 		2: "IN_PROGRESS",
 		3: "COMPLETE",
+		4: "VERIFY_FAILED",
+		5: "CANCELLED",
+		6: "DEAD_LETTERED",
+		7: "FAILED",
+		8: "SKIPPED",
 	}
 	CopyStatus_value = map[string]int32{
-		"UNKNOWN":     0,
-		"IN_QUEUE":    1,
-		"IN_PROGRESS": 2,
-		"COMPLETE":    3,
+		"UNKNOWN":       0,
+		"IN_QUEUE":      1,
+		"IN_PROGRESS":   2,
+		"COMPLETE":      3,
+		"VERIFY_FAILED": 4,
+		"CANCELLED":     5,
+		"DEAD_LETTERED": 6,
+		"FAILED":        7,
+		"SKIPPED":       8,
 	}
 )
 
@@ -77,28 +92,466 @@ func (CopyStatus) EnumDescriptor() ([]byte, []int) {
 	return file_filecopier_proto_rawDescGZIP(), []int{0}
 }
 
+// When a copy goes ahead, given what's already at the destination
+type CopyCondition int32
+
+const (
+	CopyCondition_ALWAYS_COPY CopyCondition = 0
+	CopyCondition_IF_MISSING  CopyCondition = 1
+	// If the source was modified after the destination
+	CopyCondition_IF_NEWER                 CopyCondition = 2
+	CopyCondition_IF_SIZE_OR_MTIME_DIFFERS CopyCondition = 3
+	// If the checksums differ, using the copy's checksum algorithm
+	CopyCondition_IF_HASH_DIFFERS CopyCondition = 4
+)
+
+// Enum value maps for CopyCondition.
+var (
+	CopyCondition_name = map[int32]string{
+		0: "ALWAYS_COPY",
+		1: "IF_MISSING",
+		2: "IF_NEWER",
+		3: "IF_SIZE_OR_MTIME_DIFFERS",
+		4: "IF_HASH_DIFFERS",
+	}
+	CopyCondition_value = map[string]int32{
+		"ALWAYS_COPY":              0,
+		"IF_MISSING":               1,
+		"IF_NEWER":                 2,
+		"IF_SIZE_OR_MTIME_DIFFERS": 3,
+		"IF_HASH_DIFFERS":          4,
+	}
+)
+
+func (x CopyCondition) Enum() *CopyCondition {
+	p := new(CopyCondition)
+	*p = x
+	return p
+}
+
+func (x CopyCondition) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CopyCondition) Descriptor() protoreflect.EnumDescriptor {
+	return file_filecopier_proto_enumTypes[1].Descriptor()
+}
+
+func (CopyCondition) Type() protoreflect.EnumType {
+	return &file_filecopier_proto_enumTypes[1]
+}
+
+func (x CopyCondition) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CopyCondition.Descriptor instead.
+func (CopyCondition) EnumDescriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{1}
+}
+
+type TransportType int32
+
+const (
+	TransportType_DEFAULT_TRANSPORT TransportType = 0
+	TransportType_SCP               TransportType = 1
+	TransportType_RSYNC             TransportType = 2
+	TransportType_LOCAL             TransportType = 3
+	TransportType_GRPC_STREAM       TransportType = 4
+)
+
+// Enum value maps for TransportType.
+var (
+	TransportType_name = map[int32]string{
+		0: "DEFAULT_TRANSPORT",
+		1: "SCP",
+		2: "RSYNC",
+		3: "LOCAL",
+		4: "GRPC_STREAM",
+	}
+	TransportType_value = map[string]int32{
+		"DEFAULT_TRANSPORT": 0,
+		"SCP":               1,
+		"RSYNC":             2,
+		"LOCAL":             3,
+		"GRPC_STREAM":       4,
+	}
+)
+
+func (x TransportType) Enum() *TransportType {
+	p := new(TransportType)
+	*p = x
+	return p
+}
+
+func (x TransportType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TransportType) Descriptor() protoreflect.EnumDescriptor {
+	return file_filecopier_proto_enumTypes[2].Descriptor()
+}
+
+func (TransportType) Type() protoreflect.EnumType {
+	return &file_filecopier_proto_enumTypes[2]
+}
+
+func (x TransportType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TransportType.Descriptor instead.
+func (TransportType) EnumDescriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{2}
+}
+
+// What a copy does with symbolic links
+type SymlinkPolicy int32
+
+const (
+	// Copy what the link points to, as scp does
+	SymlinkPolicy_FOLLOW_SYMLINKS SymlinkPolicy = 0
+	// Make the same link at the destination
+	SymlinkPolicy_COPY_SYMLINKS SymlinkPolicy = 1
+	SymlinkPolicy_SKIP_SYMLINKS SymlinkPolicy = 2
+)
+
+// Enum value maps for SymlinkPolicy.
+var (
+	SymlinkPolicy_name = map[int32]string{
+		0: "FOLLOW_SYMLINKS",
+		1: "COPY_SYMLINKS",
+		2: "SKIP_SYMLINKS",
+	}
+	SymlinkPolicy_value = map[string]int32{
+		"FOLLOW_SYMLINKS": 0,
+		"COPY_SYMLINKS":   1,
+		"SKIP_SYMLINKS":   2,
+	}
+)
+
+func (x SymlinkPolicy) Enum() *SymlinkPolicy {
+	p := new(SymlinkPolicy)
+	*p = x
+	return p
+}
+
+func (x SymlinkPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SymlinkPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_filecopier_proto_enumTypes[3].Descriptor()
+}
+
+func (SymlinkPolicy) Type() protoreflect.EnumType {
+	return &file_filecopier_proto_enumTypes[3]
+}
+
+func (x SymlinkPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SymlinkPolicy.Descriptor instead.
+func (SymlinkPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{3}
+}
+
+// What a directory copy does with pipes, sockets and devices, which can't be copied
+type SpecialFilePolicy int32
+
+const (
+	SpecialFilePolicy_SKIP_SPECIAL_FILES    SpecialFilePolicy = 0
+	SpecialFilePolicy_FAIL_ON_SPECIAL_FILES SpecialFilePolicy = 1
+)
+
+// Enum value maps for SpecialFilePolicy.
+var (
+	SpecialFilePolicy_name = map[int32]string{
+		0: "SKIP_SPECIAL_FILES",
+		1: "FAIL_ON_SPECIAL_FILES",
+	}
+	SpecialFilePolicy_value = map[string]int32{
+		"SKIP_SPECIAL_FILES":    0,
+		"FAIL_ON_SPECIAL_FILES": 1,
+	}
+)
+
+func (x SpecialFilePolicy) Enum() *SpecialFilePolicy {
+	p := new(SpecialFilePolicy)
+	*p = x
+	return p
+}
+
+func (x SpecialFilePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SpecialFilePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_filecopier_proto_enumTypes[4].Descriptor()
+}
+
+func (SpecialFilePolicy) Type() protoreflect.EnumType {
+	return &file_filecopier_proto_enumTypes[4]
+}
+
+func (x SpecialFilePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SpecialFilePolicy.Descriptor instead.
+func (SpecialFilePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{4}
+}
+
+// How the contents of a file are sent
+type TransferMode int32
+
+const (
+	TransferMode_FULL_TRANSFER TransferMode = 0
+	// Only send what's changed from the file already at the destination, this
+	// goes between filecopier servers whatever the transport
+	TransferMode_DELTA_TRANSFER TransferMode = 1
+)
+
+// Enum value maps for TransferMode.
+var (
+	TransferMode_name = map[int32]string{
+		0: "FULL_TRANSFER",
+		1: "DELTA_TRANSFER",
+	}
+	TransferMode_value = map[string]int32{
+		"FULL_TRANSFER":  0,
+		"DELTA_TRANSFER": 1,
+	}
+)
+
+func (x TransferMode) Enum() *TransferMode {
+	p := new(TransferMode)
+	*p = x
+	return p
+}
+
+func (x TransferMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TransferMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_filecopier_proto_enumTypes[5].Descriptor()
+}
+
+func (TransferMode) Type() protoreflect.EnumType {
+	return &file_filecopier_proto_enumTypes[5]
+}
+
+func (x TransferMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TransferMode.Descriptor instead.
+func (TransferMode) EnumDescriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{5}
+}
+
+type HashAlgorithm int32
+
+const (
+	HashAlgorithm_SHA256   HashAlgorithm = 0
+	HashAlgorithm_XXHASH64 HashAlgorithm = 1
+)
+
+// Enum value maps for HashAlgorithm.
+var (
+	HashAlgorithm_name = map[int32]string{
+		0: "SHA256",
+		1: "XXHASH64",
+	}
+	HashAlgorithm_value = map[string]int32{
+		"SHA256":   0,
+		"XXHASH64": 1,
+	}
+)
+
+func (x HashAlgorithm) Enum() *HashAlgorithm {
+	p := new(HashAlgorithm)
+	*p = x
+	return p
+}
+
+func (x HashAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HashAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_filecopier_proto_enumTypes[6].Descriptor()
+}
+
+func (HashAlgorithm) Type() protoreflect.EnumType {
+	return &file_filecopier_proto_enumTypes[6]
+}
+
+func (x HashAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HashAlgorithm.Descriptor instead.
+func (HashAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{6}
+}
+
+type CallbackState int32
+
+const (
+	CallbackState_NO_CALLBACK        CallbackState = 0
+	CallbackState_CALLBACK_PENDING   CallbackState = 1
+	CallbackState_CALLBACK_DELIVERED CallbackState = 2
+)
+
+// Enum value maps for CallbackState.
+var (
+	CallbackState_name = map[int32]string{
+		0: "NO_CALLBACK",
+		1: "CALLBACK_PENDING",
+		2: "CALLBACK_DELIVERED",
+	}
+	CallbackState_value = map[string]int32{
+		"NO_CALLBACK":        0,
+		"CALLBACK_PENDING":   1,
+		"CALLBACK_DELIVERED": 2,
+	}
+)
+
+func (x CallbackState) Enum() *CallbackState {
+	p := new(CallbackState)
+	*p = x
+	return p
+}
+
+func (x CallbackState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CallbackState) Descriptor() protoreflect.EnumDescriptor {
+	return file_filecopier_proto_enumTypes[7].Descriptor()
+}
+
+func (CallbackState) Type() protoreflect.EnumType {
+	return &file_filecopier_proto_enumTypes[7]
+}
+
+func (x CallbackState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CallbackState.Descriptor instead.
+func (CallbackState) EnumDescriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{7}
+}
+
+type PlannedOperation_Action int32
+
+const (
+	PlannedOperation_COPY     PlannedOperation_Action = 0
+	PlannedOperation_MAKE_DIR PlannedOperation_Action = 1
+	PlannedOperation_REMOVE   PlannedOperation_Action = 2
+	PlannedOperation_TRASH    PlannedOperation_Action = 3
+	PlannedOperation_LINK     PlannedOperation_Action = 4
+	PlannedOperation_SYMLINK  PlannedOperation_Action = 5
+)
+
+// Enum value maps for PlannedOperation_Action.
+var (
+	PlannedOperation_Action_name = map[int32]string{
+		0: "COPY",
+		1: "MAKE_DIR",
+		2: "REMOVE",
+		3: "TRASH",
+		4: "LINK",
+		5: "SYMLINK",
+	}
+	PlannedOperation_Action_value = map[string]int32{
+		"COPY":     0,
+		"MAKE_DIR": 1,
+		"REMOVE":   2,
+		"TRASH":    3,
+		"LINK":     4,
+		"SYMLINK":  5,
+	}
+)
+
+func (x PlannedOperation_Action) Enum() *PlannedOperation_Action {
+	p := new(PlannedOperation_Action)
+	*p = x
+	return p
+}
+
+func (x PlannedOperation_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PlannedOperation_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_filecopier_proto_enumTypes[8].Descriptor()
+}
+
+func (PlannedOperation_Action) Type() protoreflect.EnumType {
+	return &file_filecopier_proto_enumTypes[8]
+}
+
+func (x PlannedOperation_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PlannedOperation_Action.Descriptor instead.
+func (PlannedOperation_Action) EnumDescriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{5, 0}
+}
+
 type CopyRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	InputFile    string                 `protobuf:"bytes,1,opt,name=input_file,json=inputFile,proto3" json:"input_file,omitempty"`
+	InputServer  string                 `protobuf:"bytes,2,opt,name=input_server,json=inputServer,proto3" json:"input_server,omitempty"`
+	OutputFile   string                 `protobuf:"bytes,3,opt,name=output_file,json=outputFile,proto3" json:"output_file,omitempty"`
+	OutputServer string                 `protobuf:"bytes,4,opt,name=output_server,json=outputServer,proto3" json:"output_server,omitempty"`
+	Priority     int32                  `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
+	Key          int64                  `protobuf:"varint,6,opt,name=key,proto3" json:"key,omitempty"`
+	Callback     string                 `protobuf:"bytes,7,opt,name=callback,proto3" json:"callback,omitempty"`
+	Override     bool                   `protobuf:"varint,8,opt,name=override,proto3" json:"override,omitempty"`
+	Transport    TransportType          `protobuf:"varint,9,opt,name=transport,proto3,enum=filecopier.TransportType" json:"transport,omitempty"`
+	// The copy is checked by comparing checksums of both ends once it is done
+	ChecksumAlgorithm HashAlgorithm `protobuf:"varint,10,opt,name=checksum_algorithm,json=checksumAlgorithm,proto3,enum=filecopier.HashAlgorithm" json:"checksum_algorithm,omitempty"`
+	SkipVerify        bool          `protobuf:"varint,11,opt,name=skip_verify,json=skipVerify,proto3" json:"skip_verify,omitempty"`
+	RetryPolicy       *RetryPolicy  `protobuf:"bytes,12,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	// Gitignore style patterns picking out what a directory copy copies, with
+	// no includes everything that isn't excluded is copied
+	Include []string `protobuf:"bytes,13,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude []string `protobuf:"bytes,14,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	// A mirrored directory copy removes anything at the destination which isn't
+	// at the source, moving it under the trash directory if one is given
+	Mirror   bool   `protobuf:"varint,15,opt,name=mirror,proto3" json:"mirror,omitempty"`
+	TrashDir string `protobuf:"bytes,16,opt,name=trash_dir,json=trashDir,proto3" json:"trash_dir,omitempty"`
+	// Run the checks and work out what would be done, without doing any of it
+	DryRun bool `protobuf:"varint,17,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// What happens to the file's metadata, every transport keeps the mode and
+	// modification time as scp -p does and the policy is applied on top of that
+	Metadata     *MetadataPolicy   `protobuf:"bytes,18,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Symlinks     SymlinkPolicy     `protobuf:"varint,19,opt,name=symlinks,proto3,enum=filecopier.SymlinkPolicy" json:"symlinks,omitempty"`
+	SpecialFiles SpecialFilePolicy `protobuf:"varint,20,opt,name=special_files,json=specialFiles,proto3,enum=filecopier.SpecialFilePolicy" json:"special_files,omitempty"`
+	// Files in a directory copy which are hard linked together are linked
+	// together at the destination, rather than copied over for each link
+	PreserveHardlinks bool         `protobuf:"varint,21,opt,name=preserve_hardlinks,json=preserveHardlinks,proto3" json:"preserve_hardlinks,omitempty"`
+	TransferMode      TransferMode `protobuf:"varint,22,opt,name=transfer_mode,json=transferMode,proto3,enum=filecopier.TransferMode" json:"transfer_mode,omitempty"`
+	// Checked against the destination before anything is copied, modification
+	// times are compared to the second as that's all scp keeps
+	Condition     CopyCondition `protobuf:"varint,23,opt,name=condition,proto3,enum=filecopier.CopyCondition" json:"condition,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	InputFile    string `protobuf:"bytes,1,opt,name=input_file,json=inputFile,proto3" json:"input_file,omitempty"`
-	InputServer  string `protobuf:"bytes,2,opt,name=input_server,json=inputServer,proto3" json:"input_server,omitempty"`
-	OutputFile   string `protobuf:"bytes,3,opt,name=output_file,json=outputFile,proto3" json:"output_file,omitempty"`
-	OutputServer string `protobuf:"bytes,4,opt,name=output_server,json=outputServer,proto3" json:"output_server,omitempty"`
-	Priority     int32  `protobuf:"varint,5,opt,name=priority,proto3" json:"priority,omitempty"`
-	Key          int64  `protobuf:"varint,6,opt,name=key,proto3" json:"key,omitempty"`
-	Callback     string `protobuf:"bytes,7,opt,name=callback,proto3" json:"callback,omitempty"`
-	Override     bool   `protobuf:"varint,8,opt,name=override,proto3" json:"override,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CopyRequest) Reset() {
 	*x = CopyRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[0]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_filecopier_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CopyRequest) String() string {
@@ -109,7 +562,7 @@ func (*CopyRequest) ProtoMessage() {}
 
 func (x *CopyRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_filecopier_proto_msgTypes[0]
-	if protoimpl.UnsafeEnabled && x != nil {
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -180,136 +633,4079 @@ func (x *CopyRequest) GetOverride() bool {
 	return false
 }
 
-type CopyResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	MillisToCopy int64      `protobuf:"varint,1,opt,name=millis_to_copy,json=millisToCopy,proto3" json:"millis_to_copy,omitempty"`
-	Status       CopyStatus `protobuf:"varint,2,opt,name=status,proto3,enum=filecopier.CopyStatus" json:"status,omitempty"`
-	TimeInQueue  int64      `protobuf:"varint,3,opt,name=time_in_queue,json=timeInQueue,proto3" json:"time_in_queue,omitempty"`
-	Error        string     `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
-	IndexInQueue int32      `protobuf:"varint,5,opt,name=index_in_queue,json=indexInQueue,proto3" json:"index_in_queue,omitempty"`
-	Priority     int32      `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
-	ErrorCode    int32      `protobuf:"varint,7,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
-	Repeats      int32      `protobuf:"varint,8,opt,name=repeats,proto3" json:"repeats,omitempty"`
+func (x *CopyRequest) GetTransport() TransportType {
+	if x != nil {
+		return x.Transport
+	}
+	return TransportType_DEFAULT_TRANSPORT
 }
 
-func (x *CopyResponse) Reset() {
-	*x = CopyResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[1]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *CopyRequest) GetChecksumAlgorithm() HashAlgorithm {
+	if x != nil {
+		return x.ChecksumAlgorithm
 	}
+	return HashAlgorithm_SHA256
 }
 
-func (x *CopyResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *CopyRequest) GetSkipVerify() bool {
+	if x != nil {
+		return x.SkipVerify
+	}
+	return false
 }
 
-func (*CopyResponse) ProtoMessage() {}
-
-func (x *CopyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[1]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *CopyRequest) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use CopyResponse.ProtoReflect.Descriptor instead.
-func (*CopyResponse) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{1}
+func (x *CopyRequest) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
 }
 
-func (x *CopyResponse) GetMillisToCopy() int64 {
+func (x *CopyRequest) GetExclude() []string {
 	if x != nil {
-		return x.MillisToCopy
+		return x.Exclude
 	}
-	return 0
+	return nil
 }
 
-func (x *CopyResponse) GetStatus() CopyStatus {
+func (x *CopyRequest) GetMirror() bool {
 	if x != nil {
-		return x.Status
+		return x.Mirror
 	}
-	return CopyStatus_UNKNOWN
+	return false
 }
 
-func (x *CopyResponse) GetTimeInQueue() int64 {
+func (x *CopyRequest) GetTrashDir() string {
 	if x != nil {
-		return x.TimeInQueue
+		return x.TrashDir
+	}
+	return ""
+}
+
+func (x *CopyRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *CopyRequest) GetMetadata() *MetadataPolicy {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CopyRequest) GetSymlinks() SymlinkPolicy {
+	if x != nil {
+		return x.Symlinks
+	}
+	return SymlinkPolicy_FOLLOW_SYMLINKS
+}
+
+func (x *CopyRequest) GetSpecialFiles() SpecialFilePolicy {
+	if x != nil {
+		return x.SpecialFiles
+	}
+	return SpecialFilePolicy_SKIP_SPECIAL_FILES
+}
+
+func (x *CopyRequest) GetPreserveHardlinks() bool {
+	if x != nil {
+		return x.PreserveHardlinks
+	}
+	return false
+}
+
+func (x *CopyRequest) GetTransferMode() TransferMode {
+	if x != nil {
+		return x.TransferMode
+	}
+	return TransferMode_FULL_TRANSFER
+}
+
+func (x *CopyRequest) GetCondition() CopyCondition {
+	if x != nil {
+		return x.Condition
+	}
+	return CopyCondition_ALWAYS_COPY
+}
+
+// MetadataPolicy is applied to every copy once it's done, whichever transport ran it
+type MetadataPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Carry these over from the source
+	PreserveMode   bool `protobuf:"varint,1,opt,name=preserve_mode,json=preserveMode,proto3" json:"preserve_mode,omitempty"`
+	PreserveOwner  bool `protobuf:"varint,2,opt,name=preserve_owner,json=preserveOwner,proto3" json:"preserve_owner,omitempty"`
+	PreserveGroup  bool `protobuf:"varint,3,opt,name=preserve_group,json=preserveGroup,proto3" json:"preserve_group,omitempty"`
+	PreserveMtime  bool `protobuf:"varint,4,opt,name=preserve_mtime,json=preserveMtime,proto3" json:"preserve_mtime,omitempty"`
+	PreserveAtime  bool `protobuf:"varint,5,opt,name=preserve_atime,json=preserveAtime,proto3" json:"preserve_atime,omitempty"`
+	PreserveXattrs bool `protobuf:"varint,6,opt,name=preserve_xattrs,json=preserveXattrs,proto3" json:"preserve_xattrs,omitempty"`
+	PreserveAcls   bool `protobuf:"varint,7,opt,name=preserve_acls,json=preserveAcls,proto3" json:"preserve_acls,omitempty"`
+	// Explicit values, which win over anything preserved
+	Set           *FileMetadata `protobuf:"bytes,8,opt,name=set,proto3" json:"set,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetadataPolicy) Reset() {
+	*x = MetadataPolicy{}
+	mi := &file_filecopier_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetadataPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataPolicy) ProtoMessage() {}
+
+func (x *MetadataPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataPolicy.ProtoReflect.Descriptor instead.
+func (*MetadataPolicy) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MetadataPolicy) GetPreserveMode() bool {
+	if x != nil {
+		return x.PreserveMode
+	}
+	return false
+}
+
+func (x *MetadataPolicy) GetPreserveOwner() bool {
+	if x != nil {
+		return x.PreserveOwner
+	}
+	return false
+}
+
+func (x *MetadataPolicy) GetPreserveGroup() bool {
+	if x != nil {
+		return x.PreserveGroup
+	}
+	return false
+}
+
+func (x *MetadataPolicy) GetPreserveMtime() bool {
+	if x != nil {
+		return x.PreserveMtime
+	}
+	return false
+}
+
+func (x *MetadataPolicy) GetPreserveAtime() bool {
+	if x != nil {
+		return x.PreserveAtime
+	}
+	return false
+}
+
+func (x *MetadataPolicy) GetPreserveXattrs() bool {
+	if x != nil {
+		return x.PreserveXattrs
+	}
+	return false
+}
+
+func (x *MetadataPolicy) GetPreserveAcls() bool {
+	if x != nil {
+		return x.PreserveAcls
+	}
+	return false
+}
+
+func (x *MetadataPolicy) GetSet() *FileMetadata {
+	if x != nil {
+		return x.Set
+	}
+	return nil
+}
+
+// FileMetadata is the metadata of a file, anything unset is left alone
+type FileMetadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The permission bits, along with setuid, setgid and sticky
+	HasMode bool   `protobuf:"varint,1,opt,name=has_mode,json=hasMode,proto3" json:"has_mode,omitempty"`
+	Mode    uint32 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	// User and group names, or numeric ids
+	Owner string `protobuf:"bytes,3,opt,name=owner,proto3" json:"owner,omitempty"`
+	Group string `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`
+	// Unix nanoseconds
+	Mtime int64 `protobuf:"varint,5,opt,name=mtime,proto3" json:"mtime,omitempty"`
+	Atime int64 `protobuf:"varint,6,opt,name=atime,proto3" json:"atime,omitempty"`
+	// Extended attributes, other than the ACLs
+	Xattrs map[string][]byte `protobuf:"bytes,7,rep,name=xattrs,proto3" json:"xattrs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The raw system.posix_acl_access and system.posix_acl_default attributes
+	AccessAcl     []byte `protobuf:"bytes,8,opt,name=access_acl,json=accessAcl,proto3" json:"access_acl,omitempty"`
+	DefaultAcl    []byte `protobuf:"bytes,9,opt,name=default_acl,json=defaultAcl,proto3" json:"default_acl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileMetadata) Reset() {
+	*x = FileMetadata{}
+	mi := &file_filecopier_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileMetadata) ProtoMessage() {}
+
+func (x *FileMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileMetadata.ProtoReflect.Descriptor instead.
+func (*FileMetadata) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileMetadata) GetHasMode() bool {
+	if x != nil {
+		return x.HasMode
+	}
+	return false
+}
+
+func (x *FileMetadata) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
 	}
 	return 0
 }
 
-func (x *CopyResponse) GetError() string {
+func (x *FileMetadata) GetOwner() string {
 	if x != nil {
-		return x.Error
+		return x.Owner
 	}
 	return ""
 }
 
-func (x *CopyResponse) GetIndexInQueue() int32 {
+func (x *FileMetadata) GetGroup() string {
 	if x != nil {
-		return x.IndexInQueue
+		return x.Group
+	}
+	return ""
+}
+
+func (x *FileMetadata) GetMtime() int64 {
+	if x != nil {
+		return x.Mtime
 	}
 	return 0
 }
 
-func (x *CopyResponse) GetPriority() int32 {
+func (x *FileMetadata) GetAtime() int64 {
 	if x != nil {
-		return x.Priority
+		return x.Atime
 	}
 	return 0
 }
 
-func (x *CopyResponse) GetErrorCode() int32 {
+func (x *FileMetadata) GetXattrs() map[string][]byte {
 	if x != nil {
-		return x.ErrorCode
+		return x.Xattrs
+	}
+	return nil
+}
+
+func (x *FileMetadata) GetAccessAcl() []byte {
+	if x != nil {
+		return x.AccessAcl
+	}
+	return nil
+}
+
+func (x *FileMetadata) GetDefaultAcl() []byte {
+	if x != nil {
+		return x.DefaultAcl
+	}
+	return nil
+}
+
+// RetryPolicy controls how a queued copy is retried, unset fields take the defaults
+type RetryPolicy struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MaxAttempts      int32                  `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	InitialBackoffMs int64                  `protobuf:"varint,2,opt,name=initial_backoff_ms,json=initialBackoffMs,proto3" json:"initial_backoff_ms,omitempty"`
+	MaxBackoffMs     int64                  `protobuf:"varint,3,opt,name=max_backoff_ms,json=maxBackoffMs,proto3" json:"max_backoff_ms,omitempty"`
+	// The fraction each backoff is randomly varied by, from 0 to 1, this is
+	// only defaulted when there's no policy at all
+	Jitter float64 `protobuf:"fixed64,4,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	// The grpc codes which are retried, Unavailable if empty
+	RetryableCodes []int32 `protobuf:"varint,5,rep,packed,name=retryable_codes,json=retryableCodes,proto3" json:"retryable_codes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_filecopier_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
 	}
 	return 0
 }
 
-func (x *CopyResponse) GetRepeats() int32 {
+func (x *RetryPolicy) GetInitialBackoffMs() int64 {
 	if x != nil {
-		return x.Repeats
+		return x.InitialBackoffMs
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxBackoffMs() int64 {
+	if x != nil {
+		return x.MaxBackoffMs
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetJitter() float64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetRetryableCodes() []int32 {
+	if x != nil {
+		return x.RetryableCodes
+	}
+	return nil
+}
+
+type CopyResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	MillisToCopy     int64                  `protobuf:"varint,1,opt,name=millis_to_copy,json=millisToCopy,proto3" json:"millis_to_copy,omitempty"`
+	Status           CopyStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=filecopier.CopyStatus" json:"status,omitempty"`
+	TimeInQueue      int64                  `protobuf:"varint,3,opt,name=time_in_queue,json=timeInQueue,proto3" json:"time_in_queue,omitempty"`
+	Error            string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	IndexInQueue     int32                  `protobuf:"varint,5,opt,name=index_in_queue,json=indexInQueue,proto3" json:"index_in_queue,omitempty"`
+	Priority         int32                  `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	ErrorCode        int32                  `protobuf:"varint,7,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Repeats          int32                  `protobuf:"varint,8,opt,name=repeats,proto3" json:"repeats,omitempty"`
+	Checksum         string                 `protobuf:"bytes,9,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Id               string                 `protobuf:"bytes,10,opt,name=id,proto3" json:"id,omitempty"`
+	NextAttemptTime  int64                  `protobuf:"varint,11,opt,name=next_attempt_time,json=nextAttemptTime,proto3" json:"next_attempt_time,omitempty"`
+	BytesTransferred int64                  `protobuf:"varint,12,opt,name=bytes_transferred,json=bytesTransferred,proto3" json:"bytes_transferred,omitempty"`
+	// How a running copy is getting on, totals and rates are zero when they're unknown
+	BytesDone      int64   `protobuf:"varint,13,opt,name=bytes_done,json=bytesDone,proto3" json:"bytes_done,omitempty"`
+	BytesTotal     int64   `protobuf:"varint,14,opt,name=bytes_total,json=bytesTotal,proto3" json:"bytes_total,omitempty"`
+	BytesPerSecond float64 `protobuf:"fixed64,15,opt,name=bytes_per_second,json=bytesPerSecond,proto3" json:"bytes_per_second,omitempty"`
+	EtaMillis      int64   `protobuf:"varint,16,opt,name=eta_millis,json=etaMillis,proto3" json:"eta_millis,omitempty"`
+	// The number of files and directories a directory copy left out, or one
+	// for a single copy of a symbolic link that was skipped
+	FilesSkipped int32 `protobuf:"varint,17,opt,name=files_skipped,json=filesSkipped,proto3" json:"files_skipped,omitempty"`
+	// What a mirrored directory copy is going to do to the destination
+	FilesAdded   int32 `protobuf:"varint,18,opt,name=files_added,json=filesAdded,proto3" json:"files_added,omitempty"`
+	FilesUpdated int32 `protobuf:"varint,19,opt,name=files_updated,json=filesUpdated,proto3" json:"files_updated,omitempty"`
+	FilesDeleted int32 `protobuf:"varint,20,opt,name=files_deleted,json=filesDeleted,proto3" json:"files_deleted,omitempty"`
+	// What would have been done, for a dry run
+	Plan *Plan `protobuf:"bytes,21,opt,name=plan,proto3" json:"plan,omitempty"`
+	// The metadata applied to the copy under its metadata policy
+	Metadata *FileMetadata `protobuf:"bytes,22,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// The bytes a delta transfer didn't have to send
+	BytesSaved    int64 `protobuf:"varint,23,opt,name=bytes_saved,json=bytesSaved,proto3" json:"bytes_saved,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyResponse) Reset() {
+	*x = CopyResponse{}
+	mi := &file_filecopier_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyResponse) ProtoMessage() {}
+
+func (x *CopyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyResponse.ProtoReflect.Descriptor instead.
+func (*CopyResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CopyResponse) GetMillisToCopy() int64 {
+	if x != nil {
+		return x.MillisToCopy
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetStatus() CopyStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CopyStatus_UNKNOWN
+}
+
+func (x *CopyResponse) GetTimeInQueue() int64 {
+	if x != nil {
+		return x.TimeInQueue
 	}
 	return 0
 }
 
-type KeyRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+func (x *CopyResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CopyResponse) GetIndexInQueue() int32 {
+	if x != nil {
+		return x.IndexInQueue
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetRepeats() int32 {
+	if x != nil {
+		return x.Repeats
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *CopyResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CopyResponse) GetNextAttemptTime() int64 {
+	if x != nil {
+		return x.NextAttemptTime
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetBytesTransferred() int64 {
+	if x != nil {
+		return x.BytesTransferred
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetBytesDone() int64 {
+	if x != nil {
+		return x.BytesDone
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetBytesTotal() int64 {
+	if x != nil {
+		return x.BytesTotal
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetBytesPerSecond() float64 {
+	if x != nil {
+		return x.BytesPerSecond
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetEtaMillis() int64 {
+	if x != nil {
+		return x.EtaMillis
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetFilesSkipped() int32 {
+	if x != nil {
+		return x.FilesSkipped
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetFilesAdded() int32 {
+	if x != nil {
+		return x.FilesAdded
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetFilesUpdated() int32 {
+	if x != nil {
+		return x.FilesUpdated
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetFilesDeleted() int32 {
+	if x != nil {
+		return x.FilesDeleted
+	}
+	return 0
+}
+
+func (x *CopyResponse) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+func (x *CopyResponse) GetMetadata() *FileMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CopyResponse) GetBytesSaved() int64 {
+	if x != nil {
+		return x.BytesSaved
+	}
+	return 0
+}
+
+type PlannedOperation struct {
+	state        protoimpl.MessageState  `protogen:"open.v1"`
+	Action       PlannedOperation_Action `protobuf:"varint,1,opt,name=action,proto3,enum=filecopier.PlannedOperation_Action" json:"action,omitempty"`
+	InputServer  string                  `protobuf:"bytes,2,opt,name=input_server,json=inputServer,proto3" json:"input_server,omitempty"`
+	InputFile    string                  `protobuf:"bytes,3,opt,name=input_file,json=inputFile,proto3" json:"input_file,omitempty"`
+	OutputServer string                  `protobuf:"bytes,4,opt,name=output_server,json=outputServer,proto3" json:"output_server,omitempty"`
+	OutputFile   string                  `protobuf:"bytes,5,opt,name=output_file,json=outputFile,proto3" json:"output_file,omitempty"`
+	Size         int64                   `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	// The output is already there and would be replaced
+	Overwrites    bool `protobuf:"varint,7,opt,name=overwrites,proto3" json:"overwrites,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlannedOperation) Reset() {
+	*x = PlannedOperation{}
+	mi := &file_filecopier_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlannedOperation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlannedOperation) ProtoMessage() {}
+
+func (x *PlannedOperation) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlannedOperation.ProtoReflect.Descriptor instead.
+func (*PlannedOperation) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlannedOperation) GetAction() PlannedOperation_Action {
+	if x != nil {
+		return x.Action
+	}
+	return PlannedOperation_COPY
+}
+
+func (x *PlannedOperation) GetInputServer() string {
+	if x != nil {
+		return x.InputServer
+	}
+	return ""
+}
+
+func (x *PlannedOperation) GetInputFile() string {
+	if x != nil {
+		return x.InputFile
+	}
+	return ""
+}
+
+func (x *PlannedOperation) GetOutputServer() string {
+	if x != nil {
+		return x.OutputServer
+	}
+	return ""
+}
+
+func (x *PlannedOperation) GetOutputFile() string {
+	if x != nil {
+		return x.OutputFile
+	}
+	return ""
+}
+
+func (x *PlannedOperation) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PlannedOperation) GetOverwrites() bool {
+	if x != nil {
+		return x.Overwrites
+	}
+	return false
+}
+
+type Plan struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Operations []*PlannedOperation    `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	Servers    []string               `protobuf:"bytes,2,rep,name=servers,proto3" json:"servers,omitempty"`
+	Bytes      int64                  `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// Anything that would stop the plan from working
+	Problems      []string `protobuf:"bytes,4,rep,name=problems,proto3" json:"problems,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Plan) Reset() {
+	*x = Plan{}
+	mi := &file_filecopier_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Plan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Plan) ProtoMessage() {}
+
+func (x *Plan) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Plan.ProtoReflect.Descriptor instead.
+func (*Plan) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Plan) GetOperations() []*PlannedOperation {
+	if x != nil {
+		return x.Operations
+	}
+	return nil
+}
+
+func (x *Plan) GetServers() []string {
+	if x != nil {
+		return x.Servers
+	}
+	return nil
+}
+
+func (x *Plan) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *Plan) GetProblems() []string {
+	if x != nil {
+		return x.Problems
+	}
+	return nil
+}
+
+type KeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Server        string                 `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyRequest) Reset() {
+	*x = KeyRequest{}
+	mi := &file_filecopier_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyRequest) ProtoMessage() {}
+
+func (x *KeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyRequest.ProtoReflect.Descriptor instead.
+func (*KeyRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *KeyRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *KeyRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+type KeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mykey         string                 `protobuf:"bytes,1,opt,name=mykey,proto3" json:"mykey,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyResponse) Reset() {
+	*x = KeyResponse{}
+	mi := &file_filecopier_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyResponse) ProtoMessage() {}
+
+func (x *KeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyResponse.ProtoReflect.Descriptor instead.
+func (*KeyResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *KeyResponse) GetMykey() string {
+	if x != nil {
+		return x.Mykey
+	}
+	return ""
+}
+
+type AcceptsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptsRequest) Reset() {
+	*x = AcceptsRequest{}
+	mi := &file_filecopier_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptsRequest) ProtoMessage() {}
+
+func (x *AcceptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptsRequest.ProtoReflect.Descriptor instead.
+func (*AcceptsRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AcceptsRequest) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *AcceptsRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type AcceptsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        []string               `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptsResponse) Reset() {
+	*x = AcceptsResponse{}
+	mi := &file_filecopier_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptsResponse) ProtoMessage() {}
+
+func (x *AcceptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptsResponse.ProtoReflect.Descriptor instead.
+func (*AcceptsResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AcceptsResponse) GetServer() []string {
+	if x != nil {
+		return x.Server
+	}
+	return nil
+}
+
+func (x *AcceptsResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type ExistsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExistsRequest) Reset() {
+	*x = ExistsRequest{}
+	mi := &file_filecopier_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExistsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExistsRequest) ProtoMessage() {}
+
+func (x *ExistsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExistsRequest.ProtoReflect.Descriptor instead.
+func (*ExistsRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ExistsRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ExistsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Exists        bool                   `protobuf:"varint,2,opt,name=exists,proto3" json:"exists,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExistsResponse) Reset() {
+	*x = ExistsResponse{}
+	mi := &file_filecopier_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExistsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExistsResponse) ProtoMessage() {}
+
+func (x *ExistsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExistsResponse.ProtoReflect.Descriptor instead.
+func (*ExistsResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ExistsResponse) GetExists() bool {
+	if x != nil {
+		return x.Exists
+	}
+	return false
+}
+
+type ReplicateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	DryRun        bool                   `protobuf:"varint,2,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicateRequest) Reset() {
+	*x = ReplicateRequest{}
+	mi := &file_filecopier_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicateRequest) ProtoMessage() {}
+
+func (x *ReplicateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicateRequest.ProtoReflect.Descriptor instead.
+func (*ReplicateRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReplicateRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ReplicateRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ReplicateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Servers       int32                  `protobuf:"varint,1,opt,name=servers,proto3" json:"servers,omitempty"`
+	Plan          *Plan                  `protobuf:"bytes,2,opt,name=plan,proto3" json:"plan,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplicateResponse) Reset() {
+	*x = ReplicateResponse{}
+	mi := &file_filecopier_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicateResponse) ProtoMessage() {}
+
+func (x *ReplicateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicateResponse.ProtoReflect.Descriptor instead.
+func (*ReplicateResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReplicateResponse) GetServers() int32 {
+	if x != nil {
+		return x.Servers
+	}
+	return 0
+}
+
+func (x *ReplicateResponse) GetPlan() *Plan {
+	if x != nil {
+		return x.Plan
+	}
+	return nil
+}
+
+type StatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatRequest) Reset() {
+	*x = StatRequest{}
+	mi := &file_filecopier_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatRequest) ProtoMessage() {}
+
+func (x *StatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatRequest.ProtoReflect.Descriptor instead.
+func (*StatRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *StatRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type StatResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Exists bool                   `protobuf:"varint,1,opt,name=exists,proto3" json:"exists,omitempty"`
+	IsDir  bool                   `protobuf:"varint,2,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Size   int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Mode   uint32                 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// For the directory the path is in, or would go in, free_bytes is -1
+	// if the server can't tell
+	Writable  bool  `protobuf:"varint,5,opt,name=writable,proto3" json:"writable,omitempty"`
+	FreeBytes int64 `protobuf:"varint,6,opt,name=free_bytes,json=freeBytes,proto3" json:"free_bytes,omitempty"`
+	// Where the path points, if it's a symbolic link
+	Symlink string `protobuf:"bytes,7,opt,name=symlink,proto3" json:"symlink,omitempty"`
+	// Unix nanoseconds
+	ModTime int64 `protobuf:"varint,8,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	// Not a regular file or a directory, such as a fifo, socket or device
+	Special       bool `protobuf:"varint,9,opt,name=special,proto3" json:"special,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatResponse) Reset() {
+	*x = StatResponse{}
+	mi := &file_filecopier_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatResponse) ProtoMessage() {}
+
+func (x *StatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatResponse.ProtoReflect.Descriptor instead.
+func (*StatResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StatResponse) GetExists() bool {
+	if x != nil {
+		return x.Exists
+	}
+	return false
+}
+
+func (x *StatResponse) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
+func (x *StatResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *StatResponse) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *StatResponse) GetWritable() bool {
+	if x != nil {
+		return x.Writable
+	}
+	return false
+}
+
+func (x *StatResponse) GetFreeBytes() int64 {
+	if x != nil {
+		return x.FreeBytes
+	}
+	return 0
+}
+
+func (x *StatResponse) GetSymlink() string {
+	if x != nil {
+		return x.Symlink
+	}
+	return ""
+}
+
+func (x *StatResponse) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+func (x *StatResponse) GetSpecial() bool {
+	if x != nil {
+		return x.Special
+	}
+	return false
+}
+
+type GetMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Policy        *MetadataPolicy        `protobuf:"bytes,2,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetadataRequest) Reset() {
+	*x = GetMetadataRequest{}
+	mi := &file_filecopier_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetadataRequest) ProtoMessage() {}
+
+func (x *GetMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetadataRequest.ProtoReflect.Descriptor instead.
+func (*GetMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetMetadataRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *GetMetadataRequest) GetPolicy() *MetadataPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type GetMetadataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *FileMetadata          `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetadataResponse) Reset() {
+	*x = GetMetadataResponse{}
+	mi := &file_filecopier_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetadataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetadataResponse) ProtoMessage() {}
+
+func (x *GetMetadataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetadataResponse.ProtoReflect.Descriptor instead.
+func (*GetMetadataResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetMetadataResponse) GetMetadata() *FileMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type SetMetadataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Metadata      *FileMetadata          `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMetadataRequest) Reset() {
+	*x = SetMetadataRequest{}
+	mi := &file_filecopier_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMetadataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMetadataRequest) ProtoMessage() {}
+
+func (x *SetMetadataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMetadataRequest.ProtoReflect.Descriptor instead.
+func (*SetMetadataRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetMetadataRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SetMetadataRequest) GetMetadata() *FileMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type SetMetadataResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *FileMetadata          `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetMetadataResponse) Reset() {
+	*x = SetMetadataResponse{}
+	mi := &file_filecopier_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMetadataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMetadataResponse) ProtoMessage() {}
+
+func (x *SetMetadataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMetadataResponse.ProtoReflect.Descriptor instead.
+func (*SetMetadataResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SetMetadataResponse) GetMetadata() *FileMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type FileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Mode          uint32                 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	ModTime       int64                  `protobuf:"varint,5,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	Offset        int64                  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
+	mi := &file_filecopier_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunk) ProtoMessage() {}
+
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FileChunk) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *FileChunk) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileChunk) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *FileChunk) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+func (x *FileChunk) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type PushFileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BytesWritten  int64                  `protobuf:"varint,1,opt,name=bytes_written,json=bytesWritten,proto3" json:"bytes_written,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PushFileResponse) Reset() {
+	*x = PushFileResponse{}
+	mi := &file_filecopier_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PushFileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PushFileResponse) ProtoMessage() {}
+
+func (x *PushFileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PushFileResponse.ProtoReflect.Descriptor instead.
+func (*PushFileResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PushFileResponse) GetBytesWritten() int64 {
+	if x != nil {
+		return x.BytesWritten
+	}
+	return 0
+}
+
+type PullFileRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Resume from this offset if the file still matches size and mod_time
+	Offset        int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Size          int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime       int64 `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullFileRequest) Reset() {
+	*x = PullFileRequest{}
+	mi := &file_filecopier_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullFileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullFileRequest) ProtoMessage() {}
+
+func (x *PullFileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullFileRequest.ProtoReflect.Descriptor instead.
+func (*PullFileRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PullFileRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *PullFileRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *PullFileRequest) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PullFileRequest) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+type TransferJournal struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime       int64                  `protobuf:"varint,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferJournal) Reset() {
+	*x = TransferJournal{}
+	mi := &file_filecopier_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferJournal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferJournal) ProtoMessage() {}
+
+func (x *TransferJournal) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferJournal.ProtoReflect.Descriptor instead.
+func (*TransferJournal) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *TransferJournal) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *TransferJournal) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *TransferJournal) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *TransferJournal) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+type ResumeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeRequest) Reset() {
+	*x = ResumeRequest{}
+	mi := &file_filecopier_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeRequest) ProtoMessage() {}
+
+func (x *ResumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeRequest.ProtoReflect.Descriptor instead.
+func (*ResumeRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ResumeRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type ChecksumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Algorithm     HashAlgorithm          `protobuf:"varint,2,opt,name=algorithm,proto3,enum=filecopier.HashAlgorithm" json:"algorithm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChecksumRequest) Reset() {
+	*x = ChecksumRequest{}
+	mi := &file_filecopier_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecksumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecksumRequest) ProtoMessage() {}
+
+func (x *ChecksumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecksumRequest.ProtoReflect.Descriptor instead.
+func (*ChecksumRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ChecksumRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ChecksumRequest) GetAlgorithm() HashAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return HashAlgorithm_SHA256
+}
+
+type ChecksumResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Checksum      string                 `protobuf:"bytes,1,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChecksumResponse) Reset() {
+	*x = ChecksumResponse{}
+	mi := &file_filecopier_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChecksumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChecksumResponse) ProtoMessage() {}
+
+func (x *ChecksumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChecksumResponse.ProtoReflect.Descriptor instead.
+func (*ChecksumResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ChecksumResponse) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *ChecksumResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type RenameRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameRequest) Reset() {
+	*x = RenameRequest{}
+	mi := &file_filecopier_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameRequest) ProtoMessage() {}
+
+func (x *RenameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameRequest.ProtoReflect.Descriptor instead.
+func (*RenameRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RenameRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *RenameRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type RenameResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameResponse) Reset() {
+	*x = RenameResponse{}
+	mi := &file_filecopier_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameResponse) ProtoMessage() {}
+
+func (x *RenameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameResponse.ProtoReflect.Descriptor instead.
+func (*RenameResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{29}
+}
+
+type RemoveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveRequest) Reset() {
+	*x = RemoveRequest{}
+	mi := &file_filecopier_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveRequest) ProtoMessage() {}
+
+func (x *RemoveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveRequest.ProtoReflect.Descriptor instead.
+func (*RemoveRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RemoveRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type RemoveResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveResponse) Reset() {
+	*x = RemoveResponse{}
+	mi := &file_filecopier_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveResponse) ProtoMessage() {}
+
+func (x *RemoveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveResponse.ProtoReflect.Descriptor instead.
+func (*RemoveResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{31}
+}
+
+type FileEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Relative to the directory that was listed
+	Path  string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	IsDir bool   `protobuf:"varint,2,opt,name=is_dir,json=isDir,proto3" json:"is_dir,omitempty"`
+	Size  int64  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Mode  uint32 `protobuf:"varint,4,opt,name=mode,proto3" json:"mode,omitempty"`
+	// Where a symbolic link points, for a link that's copied as a link
+	Symlink string `protobuf:"bytes,5,opt,name=symlink,proto3" json:"symlink,omitempty"`
+	// An earlier file in the listing which this one is a hard link to
+	Hardlink      string `protobuf:"bytes,6,opt,name=hardlink,proto3" json:"hardlink,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileEntry) Reset() {
+	*x = FileEntry{}
+	mi := &file_filecopier_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileEntry) ProtoMessage() {}
+
+func (x *FileEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileEntry.ProtoReflect.Descriptor instead.
+func (*FileEntry) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *FileEntry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *FileEntry) GetIsDir() bool {
+	if x != nil {
+		return x.IsDir
+	}
+	return false
+}
+
+func (x *FileEntry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileEntry) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *FileEntry) GetSymlink() string {
+	if x != nil {
+		return x.Symlink
+	}
+	return ""
+}
+
+func (x *FileEntry) GetHardlink() string {
+	if x != nil {
+		return x.Hardlink
+	}
+	return ""
+}
+
+type ListDirRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Path              string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Include           []string               `protobuf:"bytes,2,rep,name=include,proto3" json:"include,omitempty"`
+	Exclude           []string               `protobuf:"bytes,3,rep,name=exclude,proto3" json:"exclude,omitempty"`
+	Symlinks          SymlinkPolicy          `protobuf:"varint,4,opt,name=symlinks,proto3,enum=filecopier.SymlinkPolicy" json:"symlinks,omitempty"`
+	SpecialFiles      SpecialFilePolicy      `protobuf:"varint,5,opt,name=special_files,json=specialFiles,proto3,enum=filecopier.SpecialFilePolicy" json:"special_files,omitempty"`
+	PreserveHardlinks bool                   `protobuf:"varint,6,opt,name=preserve_hardlinks,json=preserveHardlinks,proto3" json:"preserve_hardlinks,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListDirRequest) Reset() {
+	*x = ListDirRequest{}
+	mi := &file_filecopier_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDirRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDirRequest) ProtoMessage() {}
+
+func (x *ListDirRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDirRequest.ProtoReflect.Descriptor instead.
+func (*ListDirRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListDirRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ListDirRequest) GetInclude() []string {
+	if x != nil {
+		return x.Include
+	}
+	return nil
+}
+
+func (x *ListDirRequest) GetExclude() []string {
+	if x != nil {
+		return x.Exclude
+	}
+	return nil
+}
+
+func (x *ListDirRequest) GetSymlinks() SymlinkPolicy {
+	if x != nil {
+		return x.Symlinks
+	}
+	return SymlinkPolicy_FOLLOW_SYMLINKS
+}
+
+func (x *ListDirRequest) GetSpecialFiles() SpecialFilePolicy {
+	if x != nil {
+		return x.SpecialFiles
+	}
+	return SpecialFilePolicy_SKIP_SPECIAL_FILES
+}
+
+func (x *ListDirRequest) GetPreserveHardlinks() bool {
+	if x != nil {
+		return x.PreserveHardlinks
+	}
+	return false
+}
+
+type ListDirResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Entries []*FileEntry           `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	// Files and directories left out by the patterns, a directory counts once
+	Skipped       int32 `protobuf:"varint,2,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDirResponse) Reset() {
+	*x = ListDirResponse{}
+	mi := &file_filecopier_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDirResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDirResponse) ProtoMessage() {}
+
+func (x *ListDirResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDirResponse.ProtoReflect.Descriptor instead.
+func (*ListDirResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ListDirResponse) GetEntries() []*FileEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ListDirResponse) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+type SignatureRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Picked from the size of the file if it's not given, or if there'd be too
+	// many blocks to send
+	BlockSize     int32 `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SignatureRequest) Reset() {
+	*x = SignatureRequest{}
+	mi := &file_filecopier_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureRequest) ProtoMessage() {}
+
+func (x *SignatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureRequest.ProtoReflect.Descriptor instead.
+func (*SignatureRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SignatureRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SignatureRequest) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+type BlockSignature struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The rolling checksum, and a strong hash to confirm a match
+	Weak          uint32 `protobuf:"varint,1,opt,name=weak,proto3" json:"weak,omitempty"`
+	Strong        []byte `protobuf:"bytes,2,opt,name=strong,proto3" json:"strong,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockSignature) Reset() {
+	*x = BlockSignature{}
+	mi := &file_filecopier_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockSignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockSignature) ProtoMessage() {}
+
+func (x *BlockSignature) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockSignature.ProtoReflect.Descriptor instead.
+func (*BlockSignature) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *BlockSignature) GetWeak() uint32 {
+	if x != nil {
+		return x.Weak
+	}
+	return 0
+}
+
+func (x *BlockSignature) GetStrong() []byte {
+	if x != nil {
+		return x.Strong
+	}
+	return nil
+}
+
+// Signature describes the blocks of a file, a file that isn't there has none
+type Signature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlockSize     int32                  `protobuf:"varint,1,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Blocks        []*BlockSignature      `protobuf:"bytes,3,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Signature) Reset() {
+	*x = Signature{}
+	mi := &file_filecopier_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Signature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Signature) ProtoMessage() {}
+
+func (x *Signature) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Signature.ProtoReflect.Descriptor instead.
+func (*Signature) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *Signature) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *Signature) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Signature) GetBlocks() []*BlockSignature {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+type DeltaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Basis         *Signature             `protobuf:"bytes,2,opt,name=basis,proto3" json:"basis,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeltaRequest) Reset() {
+	*x = DeltaRequest{}
+	mi := &file_filecopier_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeltaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeltaRequest) ProtoMessage() {}
+
+func (x *DeltaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeltaRequest.ProtoReflect.Descriptor instead.
+func (*DeltaRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DeltaRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DeltaRequest) GetBasis() *Signature {
+	if x != nil {
+		return x.Basis
+	}
+	return nil
+}
+
+// DeltaOp is a step in rebuilding a file from a basis, either a run of the
+// basis's blocks or data that's new
+type DeltaOp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set on the first op, the size of the file being rebuilt
+	Size      int64 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	BlockSize int32 `protobuf:"varint,2,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	// Set on the first op sent to ApplyDelta
+	BasisPath string `protobuf:"bytes,3,opt,name=basis_path,json=basisPath,proto3" json:"basis_path,omitempty"`
+	Path      string `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	Block     int64  `protobuf:"varint,5,opt,name=block,proto3" json:"block,omitempty"`
+	Blocks    int64  `protobuf:"varint,6,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	Data      []byte `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
+	// Set on the first op, kept as scp -p does
+	Mode          uint32 `protobuf:"varint,8,opt,name=mode,proto3" json:"mode,omitempty"`
+	ModTime       int64  `protobuf:"varint,9,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeltaOp) Reset() {
+	*x = DeltaOp{}
+	mi := &file_filecopier_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeltaOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeltaOp) ProtoMessage() {}
+
+func (x *DeltaOp) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeltaOp.ProtoReflect.Descriptor instead.
+func (*DeltaOp) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DeltaOp) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DeltaOp) GetBlockSize() int32 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *DeltaOp) GetBasisPath() string {
+	if x != nil {
+		return x.BasisPath
+	}
+	return ""
+}
+
+func (x *DeltaOp) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DeltaOp) GetBlock() int64 {
+	if x != nil {
+		return x.Block
+	}
+	return 0
+}
+
+func (x *DeltaOp) GetBlocks() int64 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+func (x *DeltaOp) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *DeltaOp) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *DeltaOp) GetModTime() int64 {
+	if x != nil {
+		return x.ModTime
+	}
+	return 0
+}
+
+type ApplyDeltaResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Size  int64                  `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	// The bytes which came from the basis
+	Reused        int64 `protobuf:"varint,2,opt,name=reused,proto3" json:"reused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyDeltaResponse) Reset() {
+	*x = ApplyDeltaResponse{}
+	mi := &file_filecopier_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyDeltaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyDeltaResponse) ProtoMessage() {}
+
+func (x *ApplyDeltaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyDeltaResponse.ProtoReflect.Descriptor instead.
+func (*ApplyDeltaResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ApplyDeltaResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *ApplyDeltaResponse) GetReused() int64 {
+	if x != nil {
+		return x.Reused
+	}
+	return 0
+}
+
+type MakeDirRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Mode          uint32                 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MakeDirRequest) Reset() {
+	*x = MakeDirRequest{}
+	mi := &file_filecopier_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MakeDirRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeDirRequest) ProtoMessage() {}
+
+func (x *MakeDirRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeDirRequest.ProtoReflect.Descriptor instead.
+func (*MakeDirRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *MakeDirRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *MakeDirRequest) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+type MakeDirResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MakeDirResponse) Reset() {
+	*x = MakeDirResponse{}
+	mi := &file_filecopier_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MakeDirResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MakeDirResponse) ProtoMessage() {}
+
+func (x *MakeDirResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MakeDirResponse.ProtoReflect.Descriptor instead.
+func (*MakeDirResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{42}
+}
+
+// LinkRequest makes a hard link at the path to the target, replacing anything already there
+type LinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkRequest) Reset() {
+	*x = LinkRequest{}
+	mi := &file_filecopier_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkRequest) ProtoMessage() {}
+
+func (x *LinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkRequest.ProtoReflect.Descriptor instead.
+func (*LinkRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *LinkRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *LinkRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type LinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkResponse) Reset() {
+	*x = LinkResponse{}
+	mi := &file_filecopier_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkResponse) ProtoMessage() {}
+
+func (x *LinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkResponse.ProtoReflect.Descriptor instead.
+func (*LinkResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{44}
+}
+
+// SymlinkRequest makes a symbolic link at the path to the target, replacing anything already there
+type SymlinkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymlinkRequest) Reset() {
+	*x = SymlinkRequest{}
+	mi := &file_filecopier_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymlinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymlinkRequest) ProtoMessage() {}
+
+func (x *SymlinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymlinkRequest.ProtoReflect.Descriptor instead.
+func (*SymlinkRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *SymlinkRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *SymlinkRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type SymlinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymlinkResponse) Reset() {
+	*x = SymlinkResponse{}
+	mi := &file_filecopier_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymlinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymlinkResponse) ProtoMessage() {}
+
+func (x *SymlinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymlinkResponse.ProtoReflect.Descriptor instead.
+func (*SymlinkResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{46}
+}
+
+// DirJob is a directory copy, which runs as a copy for each of its files
+type DirJob struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Req    *CopyRequest           `protobuf:"bytes,2,opt,name=req,proto3" json:"req,omitempty"`
+	Status CopyStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=filecopier.CopyStatus" json:"status,omitempty"`
+	// Directories still to be created and files still to be queued
+	Dirs    []*FileEntry `protobuf:"bytes,4,rep,name=dirs,proto3" json:"dirs,omitempty"`
+	Pending []*FileEntry `protobuf:"bytes,5,rep,name=pending,proto3" json:"pending,omitempty"`
+	// The ids of the file copies which are queued or running
+	Active           []string `protobuf:"bytes,6,rep,name=active,proto3" json:"active,omitempty"`
+	FilesTotal       int32    `protobuf:"varint,7,opt,name=files_total,json=filesTotal,proto3" json:"files_total,omitempty"`
+	FilesComplete    int32    `protobuf:"varint,8,opt,name=files_complete,json=filesComplete,proto3" json:"files_complete,omitempty"`
+	FilesFailed      int32    `protobuf:"varint,9,opt,name=files_failed,json=filesFailed,proto3" json:"files_failed,omitempty"`
+	BytesTransferred int64    `protobuf:"varint,10,opt,name=bytes_transferred,json=bytesTransferred,proto3" json:"bytes_transferred,omitempty"`
+	// The most recent thing to go wrong
+	Error        string `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	TimeAdded    int64  `protobuf:"varint,12,opt,name=time_added,json=timeAdded,proto3" json:"time_added,omitempty"`
+	TimeFinished int64  `protobuf:"varint,13,opt,name=time_finished,json=timeFinished,proto3" json:"time_finished,omitempty"`
+	FilesSkipped int32  `protobuf:"varint,14,opt,name=files_skipped,json=filesSkipped,proto3" json:"files_skipped,omitempty"`
+	// Things at the destination a mirrored copy still has to remove
+	Extraneous   []*FileEntry `protobuf:"bytes,15,rep,name=extraneous,proto3" json:"extraneous,omitempty"`
+	FilesAdded   int32        `protobuf:"varint,16,opt,name=files_added,json=filesAdded,proto3" json:"files_added,omitempty"`
+	FilesUpdated int32        `protobuf:"varint,17,opt,name=files_updated,json=filesUpdated,proto3" json:"files_updated,omitempty"`
+	FilesDeleted int32        `protobuf:"varint,18,opt,name=files_deleted,json=filesDeleted,proto3" json:"files_deleted,omitempty"`
+	// Links still to be made, once everything they could point to is copied
+	Links         []*FileEntry `protobuf:"bytes,19,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DirJob) Reset() {
+	*x = DirJob{}
+	mi := &file_filecopier_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirJob) ProtoMessage() {}
+
+func (x *DirJob) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirJob.ProtoReflect.Descriptor instead.
+func (*DirJob) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *DirJob) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DirJob) GetReq() *CopyRequest {
+	if x != nil {
+		return x.Req
+	}
+	return nil
+}
+
+func (x *DirJob) GetStatus() CopyStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CopyStatus_UNKNOWN
+}
+
+func (x *DirJob) GetDirs() []*FileEntry {
+	if x != nil {
+		return x.Dirs
+	}
+	return nil
+}
+
+func (x *DirJob) GetPending() []*FileEntry {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+func (x *DirJob) GetActive() []string {
+	if x != nil {
+		return x.Active
+	}
+	return nil
+}
+
+func (x *DirJob) GetFilesTotal() int32 {
+	if x != nil {
+		return x.FilesTotal
+	}
+	return 0
+}
+
+func (x *DirJob) GetFilesComplete() int32 {
+	if x != nil {
+		return x.FilesComplete
+	}
+	return 0
+}
+
+func (x *DirJob) GetFilesFailed() int32 {
+	if x != nil {
+		return x.FilesFailed
+	}
+	return 0
+}
+
+func (x *DirJob) GetBytesTransferred() int64 {
+	if x != nil {
+		return x.BytesTransferred
+	}
+	return 0
+}
+
+func (x *DirJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DirJob) GetTimeAdded() int64 {
+	if x != nil {
+		return x.TimeAdded
+	}
+	return 0
+}
+
+func (x *DirJob) GetTimeFinished() int64 {
+	if x != nil {
+		return x.TimeFinished
+	}
+	return 0
+}
+
+func (x *DirJob) GetFilesSkipped() int32 {
+	if x != nil {
+		return x.FilesSkipped
+	}
+	return 0
+}
+
+func (x *DirJob) GetExtraneous() []*FileEntry {
+	if x != nil {
+		return x.Extraneous
+	}
+	return nil
+}
+
+func (x *DirJob) GetFilesAdded() int32 {
+	if x != nil {
+		return x.FilesAdded
+	}
+	return 0
+}
+
+func (x *DirJob) GetFilesUpdated() int32 {
+	if x != nil {
+		return x.FilesUpdated
+	}
+	return 0
+}
+
+func (x *DirJob) GetFilesDeleted() int32 {
+	if x != nil {
+		return x.FilesDeleted
+	}
+	return 0
+}
+
+func (x *DirJob) GetLinks() []*FileEntry {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type DirJobs struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Jobs          []*DirJob              `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DirJobs) Reset() {
+	*x = DirJobs{}
+	mi := &file_filecopier_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirJobs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirJobs) ProtoMessage() {}
+
+func (x *DirJobs) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirJobs.ProtoReflect.Descriptor instead.
+func (*DirJobs) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *DirJobs) GetJobs() []*DirJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type TempFile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Server        string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TempFile) Reset() {
+	*x = TempFile{}
+	mi := &file_filecopier_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TempFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TempFile) ProtoMessage() {}
+
+func (x *TempFile) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TempFile.ProtoReflect.Descriptor instead.
+func (*TempFile) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *TempFile) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *TempFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type TempFiles struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Files         []*TempFile            `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TempFiles) Reset() {
+	*x = TempFiles{}
+	mi := &file_filecopier_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TempFiles) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TempFiles) ProtoMessage() {}
+
+func (x *TempFiles) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TempFiles.ProtoReflect.Descriptor instead.
+func (*TempFiles) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *TempFiles) GetFiles() []*TempFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type QueueEntry struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Req       *CopyRequest           `protobuf:"bytes,1,opt,name=req,proto3" json:"req,omitempty"`
+	Resp      *CopyResponse          `protobuf:"bytes,2,opt,name=resp,proto3" json:"resp,omitempty"`
+	TimeAdded int64                  `protobuf:"varint,3,opt,name=time_added,json=timeAdded,proto3" json:"time_added,omitempty"`
+	// Marks an entry which has been dropped from the queue
+	Removed      bool  `protobuf:"varint,4,opt,name=removed,proto3" json:"removed,omitempty"`
+	TimeStarted  int64 `protobuf:"varint,5,opt,name=time_started,json=timeStarted,proto3" json:"time_started,omitempty"`
+	TimeFinished int64 `protobuf:"varint,6,opt,name=time_finished,json=timeFinished,proto3" json:"time_finished,omitempty"`
+	// How the callback for this copy is getting on, filled in when reporting status
+	Callback      *CallbackDelivery `protobuf:"bytes,7,opt,name=callback,proto3" json:"callback,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueEntry) Reset() {
+	*x = QueueEntry{}
+	mi := &file_filecopier_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueEntry) ProtoMessage() {}
+
+func (x *QueueEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueEntry.ProtoReflect.Descriptor instead.
+func (*QueueEntry) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *QueueEntry) GetReq() *CopyRequest {
+	if x != nil {
+		return x.Req
+	}
+	return nil
+}
+
+func (x *QueueEntry) GetResp() *CopyResponse {
+	if x != nil {
+		return x.Resp
+	}
+	return nil
+}
+
+func (x *QueueEntry) GetTimeAdded() int64 {
+	if x != nil {
+		return x.TimeAdded
+	}
+	return 0
+}
+
+func (x *QueueEntry) GetRemoved() bool {
+	if x != nil {
+		return x.Removed
+	}
+	return false
+}
+
+func (x *QueueEntry) GetTimeStarted() int64 {
+	if x != nil {
+		return x.TimeStarted
+	}
+	return 0
+}
+
+func (x *QueueEntry) GetTimeFinished() int64 {
+	if x != nil {
+		return x.TimeFinished
+	}
+	return 0
+}
+
+func (x *QueueEntry) GetCallback() *CallbackDelivery {
+	if x != nil {
+		return x.Callback
+	}
+	return nil
+}
+
+type CallbackDelivery struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Server          string                 `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Request         *CallbackRequest       `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	State           CallbackState          `protobuf:"varint,3,opt,name=state,proto3,enum=filecopier.CallbackState" json:"state,omitempty"`
+	Attempts        int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptTime int64                  `protobuf:"varint,5,opt,name=next_attempt_time,json=nextAttemptTime,proto3" json:"next_attempt_time,omitempty"`
+	LastError       string                 `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	DeliveredTime   int64                  `protobuf:"varint,7,opt,name=delivered_time,json=deliveredTime,proto3" json:"delivered_time,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CallbackDelivery) Reset() {
+	*x = CallbackDelivery{}
+	mi := &file_filecopier_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallbackDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallbackDelivery) ProtoMessage() {}
+
+func (x *CallbackDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallbackDelivery.ProtoReflect.Descriptor instead.
+func (*CallbackDelivery) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *CallbackDelivery) GetServer() string {
+	if x != nil {
+		return x.Server
+	}
+	return ""
+}
+
+func (x *CallbackDelivery) GetRequest() *CallbackRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *CallbackDelivery) GetState() CallbackState {
+	if x != nil {
+		return x.State
+	}
+	return CallbackState_NO_CALLBACK
+}
+
+func (x *CallbackDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *CallbackDelivery) GetNextAttemptTime() int64 {
+	if x != nil {
+		return x.NextAttemptTime
+	}
+	return 0
+}
+
+func (x *CallbackDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *CallbackDelivery) GetDeliveredTime() int64 {
+	if x != nil {
+		return x.DeliveredTime
+	}
+	return 0
+}
+
+type CallbackOutbox struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*CallbackDelivery    `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CallbackOutbox) Reset() {
+	*x = CallbackOutbox{}
+	mi := &file_filecopier_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CallbackOutbox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CallbackOutbox) ProtoMessage() {}
+
+func (x *CallbackOutbox) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CallbackOutbox.ProtoReflect.Descriptor instead.
+func (*CallbackOutbox) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *CallbackOutbox) GetDeliveries() []*CallbackDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+type CopyStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Looks up by id if set, otherwise the most recent copy with this key
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key           int64  `protobuf:"varint,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyStatusRequest) Reset() {
+	*x = CopyStatusRequest{}
+	mi := &file_filecopier_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyStatusRequest) ProtoMessage() {}
+
+func (x *CopyStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyStatusRequest.ProtoReflect.Descriptor instead.
+func (*CopyStatusRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CopyStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CopyStatusRequest) GetKey() int64 {
+	if x != nil {
+		return x.Key
+	}
+	return 0
+}
+
+type WatchCopyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Watches by id if set, otherwise the most recent copy with this key
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key           int64  `protobuf:"varint,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCopyRequest) Reset() {
+	*x = WatchCopyRequest{}
+	mi := &file_filecopier_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCopyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCopyRequest) ProtoMessage() {}
+
+func (x *WatchCopyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCopyRequest.ProtoReflect.Descriptor instead.
+func (*WatchCopyRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *WatchCopyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *WatchCopyRequest) GetKey() int64 {
+	if x != nil {
+		return x.Key
+	}
+	return 0
+}
+
+// CopyEvent is sent whenever a watched copy changes state, and every so often while it runs
+type CopyEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Entry          *QueueEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	Status         CopyStatus             `protobuf:"varint,2,opt,name=status,proto3,enum=filecopier.CopyStatus" json:"status,omitempty"`
+	BytesDone      int64                  `protobuf:"varint,3,opt,name=bytes_done,json=bytesDone,proto3" json:"bytes_done,omitempty"`
+	BytesTotal     int64                  `protobuf:"varint,4,opt,name=bytes_total,json=bytesTotal,proto3" json:"bytes_total,omitempty"`
+	BytesPerSecond float64                `protobuf:"fixed64,5,opt,name=bytes_per_second,json=bytesPerSecond,proto3" json:"bytes_per_second,omitempty"`
+	EtaMillis      int64                  `protobuf:"varint,6,opt,name=eta_millis,json=etaMillis,proto3" json:"eta_millis,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CopyEvent) Reset() {
+	*x = CopyEvent{}
+	mi := &file_filecopier_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyEvent) ProtoMessage() {}
+
+func (x *CopyEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyEvent.ProtoReflect.Descriptor instead.
+func (*CopyEvent) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CopyEvent) GetEntry() *QueueEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *CopyEvent) GetStatus() CopyStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CopyStatus_UNKNOWN
+}
+
+func (x *CopyEvent) GetBytesDone() int64 {
+	if x != nil {
+		return x.BytesDone
+	}
+	return 0
+}
+
+func (x *CopyEvent) GetBytesTotal() int64 {
+	if x != nil {
+		return x.BytesTotal
+	}
+	return 0
+}
+
+func (x *CopyEvent) GetBytesPerSecond() float64 {
+	if x != nil {
+		return x.BytesPerSecond
+	}
+	return 0
+}
+
+func (x *CopyEvent) GetEtaMillis() int64 {
+	if x != nil {
+		return x.EtaMillis
+	}
+	return 0
+}
+
+type CopyStatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Entry *QueueEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// Set instead of the entry when the id is for a directory copy
+	Job           *DirJob `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CopyStatusResponse) Reset() {
+	*x = CopyStatusResponse{}
+	mi := &file_filecopier_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CopyStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CopyStatusResponse) ProtoMessage() {}
+
+func (x *CopyStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CopyStatusResponse.ProtoReflect.Descriptor instead.
+func (*CopyStatusResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *CopyStatusResponse) GetEntry() *QueueEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *CopyStatusResponse) GetJob() *DirJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type ListQueueRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty filters match everything
+	Status        []CopyStatus `protobuf:"varint,1,rep,packed,name=status,proto3,enum=filecopier.CopyStatus" json:"status,omitempty"`
+	InputServer   string       `protobuf:"bytes,2,opt,name=input_server,json=inputServer,proto3" json:"input_server,omitempty"`
+	OutputServer  string       `protobuf:"bytes,3,opt,name=output_server,json=outputServer,proto3" json:"output_server,omitempty"`
+	Key           int64        `protobuf:"varint,4,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQueueRequest) Reset() {
+	*x = ListQueueRequest{}
+	mi := &file_filecopier_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQueueRequest) ProtoMessage() {}
+
+func (x *ListQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQueueRequest.ProtoReflect.Descriptor instead.
+func (*ListQueueRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListQueueRequest) GetStatus() []CopyStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+func (x *ListQueueRequest) GetInputServer() string {
+	if x != nil {
+		return x.InputServer
+	}
+	return ""
+}
+
+func (x *ListQueueRequest) GetOutputServer() string {
+	if x != nil {
+		return x.OutputServer
+	}
+	return ""
+}
+
+func (x *ListQueueRequest) GetKey() int64 {
+	if x != nil {
+		return x.Key
+	}
+	return 0
+}
+
+type ListQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*QueueEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	Paused        bool                   `protobuf:"varint,2,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListQueueResponse) Reset() {
+	*x = ListQueueResponse{}
+	mi := &file_filecopier_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListQueueResponse) ProtoMessage() {}
+
+func (x *ListQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListQueueResponse.ProtoReflect.Descriptor instead.
+func (*ListQueueResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ListQueueResponse) GetEntries() []*QueueEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ListQueueResponse) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type CancelCopyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Looks up by id if set, otherwise the most recent copy with this key
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Key           int64  `protobuf:"varint,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelCopyRequest) Reset() {
+	*x = CancelCopyRequest{}
+	mi := &file_filecopier_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelCopyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCopyRequest) ProtoMessage() {}
+
+func (x *CancelCopyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCopyRequest.ProtoReflect.Descriptor instead.
+func (*CancelCopyRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *CancelCopyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelCopyRequest) GetKey() int64 {
+	if x != nil {
+		return x.Key
+	}
+	return 0
+}
+
+type CancelCopyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *QueueEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelCopyResponse) Reset() {
+	*x = CancelCopyResponse{}
+	mi := &file_filecopier_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelCopyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelCopyResponse) ProtoMessage() {}
+
+func (x *CancelCopyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelCopyResponse.ProtoReflect.Descriptor instead.
+func (*CancelCopyResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *CancelCopyResponse) GetEntry() *QueueEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type PauseQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseQueueRequest) Reset() {
+	*x = PauseQueueRequest{}
+	mi := &file_filecopier_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseQueueRequest) ProtoMessage() {}
+
+func (x *PauseQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseQueueRequest.ProtoReflect.Descriptor instead.
+func (*PauseQueueRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{62}
+}
+
+type PauseQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
-
-	Key    string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Server string `protobuf:"bytes,2,opt,name=server,proto3" json:"server,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *KeyRequest) Reset() {
-	*x = KeyRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[2]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *PauseQueueResponse) Reset() {
+	*x = PauseQueueResponse{}
+	mi := &file_filecopier_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *KeyRequest) String() string {
+func (x *PauseQueueResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KeyRequest) ProtoMessage() {}
+func (*PauseQueueResponse) ProtoMessage() {}
 
-func (x *KeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[2]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *PauseQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[63]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -319,51 +4715,33 @@ func (x *KeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KeyRequest.ProtoReflect.Descriptor instead.
-func (*KeyRequest) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{2}
-}
-
-func (x *KeyRequest) GetKey() string {
-	if x != nil {
-		return x.Key
-	}
-	return ""
-}
-
-func (x *KeyRequest) GetServer() string {
-	if x != nil {
-		return x.Server
-	}
-	return ""
+// Deprecated: Use PauseQueueResponse.ProtoReflect.Descriptor instead.
+func (*PauseQueueResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{63}
 }
 
-type KeyResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+type ResumeQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
-
-	Mykey string `protobuf:"bytes,1,opt,name=mykey,proto3" json:"mykey,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *KeyResponse) Reset() {
-	*x = KeyResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[3]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *ResumeQueueRequest) Reset() {
+	*x = ResumeQueueRequest{}
+	mi := &file_filecopier_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *KeyResponse) String() string {
+func (x *ResumeQueueRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*KeyResponse) ProtoMessage() {}
+func (*ResumeQueueRequest) ProtoMessage() {}
 
-func (x *KeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[3]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *ResumeQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[64]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -373,45 +4751,33 @@ func (x *KeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use KeyResponse.ProtoReflect.Descriptor instead.
-func (*KeyResponse) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *KeyResponse) GetMykey() string {
-	if x != nil {
-		return x.Mykey
-	}
-	return ""
+// Deprecated: Use ResumeQueueRequest.ProtoReflect.Descriptor instead.
+func (*ResumeQueueRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{64}
 }
 
-type AcceptsRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+type ResumeQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
-
-	Server string `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
-	Key    string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AcceptsRequest) Reset() {
-	*x = AcceptsRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[4]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *ResumeQueueResponse) Reset() {
+	*x = ResumeQueueResponse{}
+	mi := &file_filecopier_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *AcceptsRequest) String() string {
+func (x *ResumeQueueResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AcceptsRequest) ProtoMessage() {}
+func (*ResumeQueueResponse) ProtoMessage() {}
 
-func (x *AcceptsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[4]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *ResumeQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[65]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -421,52 +4787,70 @@ func (x *AcceptsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AcceptsRequest.ProtoReflect.Descriptor instead.
-func (*AcceptsRequest) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ResumeQueueResponse.ProtoReflect.Descriptor instead.
+func (*ResumeQueueResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *AcceptsRequest) GetServer() string {
-	if x != nil {
-		return x.Server
-	}
-	return ""
+type ListDeadLettersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AcceptsRequest) GetKey() string {
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_filecopier_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[66]
 	if x != nil {
-		return x.Key
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-type AcceptsResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{66}
+}
 
-	Server []string `protobuf:"bytes,1,rep,name=server,proto3" json:"server,omitempty"`
-	Type   string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+type ListDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*QueueEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AcceptsResponse) Reset() {
-	*x = AcceptsResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[5]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_filecopier_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *AcceptsResponse) String() string {
+func (x *ListDeadLettersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AcceptsResponse) ProtoMessage() {}
+func (*ListDeadLettersResponse) ProtoMessage() {}
 
-func (x *AcceptsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[5]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[67]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -476,51 +4860,41 @@ func (x *AcceptsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AcceptsResponse.ProtoReflect.Descriptor instead.
-func (*AcceptsResponse) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{67}
 }
 
-func (x *AcceptsResponse) GetServer() []string {
+func (x *ListDeadLettersResponse) GetEntries() []*QueueEntry {
 	if x != nil {
-		return x.Server
+		return x.Entries
 	}
 	return nil
 }
 
-func (x *AcceptsResponse) GetType() string {
-	if x != nil {
-		return x.Type
-	}
-	return ""
-}
-
-type ExistsRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+type RequeueDeadLetterRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExistsRequest) Reset() {
-	*x = ExistsRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[6]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *RequeueDeadLetterRequest) Reset() {
+	*x = RequeueDeadLetterRequest{}
+	mi := &file_filecopier_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExistsRequest) String() string {
+func (x *RequeueDeadLetterRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExistsRequest) ProtoMessage() {}
+func (*RequeueDeadLetterRequest) ProtoMessage() {}
 
-func (x *ExistsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[6]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *RequeueDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[68]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -530,44 +4904,41 @@ func (x *ExistsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExistsRequest.ProtoReflect.Descriptor instead.
-func (*ExistsRequest) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RequeueDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *ExistsRequest) GetPath() string {
+func (x *RequeueDeadLetterRequest) GetId() string {
 	if x != nil {
-		return x.Path
+		return x.Id
 	}
 	return ""
 }
 
-type ExistsResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+type RequeueDeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Response      *CopyResponse          `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Exists bool `protobuf:"varint,2,opt,name=exists,proto3" json:"exists,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ExistsResponse) Reset() {
-	*x = ExistsResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[7]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *RequeueDeadLetterResponse) Reset() {
+	*x = RequeueDeadLetterResponse{}
+	mi := &file_filecopier_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ExistsResponse) String() string {
+func (x *RequeueDeadLetterResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ExistsResponse) ProtoMessage() {}
+func (*RequeueDeadLetterResponse) ProtoMessage() {}
 
-func (x *ExistsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[7]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *RequeueDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[69]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -577,44 +4948,43 @@ func (x *ExistsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ExistsResponse.ProtoReflect.Descriptor instead.
-func (*ExistsResponse) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use RequeueDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*RequeueDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{69}
 }
 
-func (x *ExistsResponse) GetExists() bool {
+func (x *RequeueDeadLetterResponse) GetResponse() *CopyResponse {
 	if x != nil {
-		return x.Exists
+		return x.Response
 	}
-	return false
+	return nil
 }
 
-type ReplicateRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+type PurgeDeadLettersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ids   []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	// Purges everything, the ids are ignored
+	All           bool `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReplicateRequest) Reset() {
-	*x = ReplicateRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[8]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *PurgeDeadLettersRequest) Reset() {
+	*x = PurgeDeadLettersRequest{}
+	mi := &file_filecopier_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReplicateRequest) String() string {
+func (x *PurgeDeadLettersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplicateRequest) ProtoMessage() {}
+func (*PurgeDeadLettersRequest) ProtoMessage() {}
 
-func (x *ReplicateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[8]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *PurgeDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[70]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -624,44 +4994,48 @@ func (x *ReplicateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplicateRequest.ProtoReflect.Descriptor instead.
-func (*ReplicateRequest) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use PurgeDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*PurgeDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *ReplicateRequest) GetPath() string {
+func (x *PurgeDeadLettersRequest) GetIds() []string {
 	if x != nil {
-		return x.Path
+		return x.Ids
 	}
-	return ""
+	return nil
 }
 
-type ReplicateResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *PurgeDeadLettersRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
 
-	Servers int32 `protobuf:"varint,1,opt,name=servers,proto3" json:"servers,omitempty"`
+type PurgeDeadLettersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purged        int32                  `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReplicateResponse) Reset() {
-	*x = ReplicateResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[9]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+func (x *PurgeDeadLettersResponse) Reset() {
+	*x = PurgeDeadLettersResponse{}
+	mi := &file_filecopier_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReplicateResponse) String() string {
+func (x *PurgeDeadLettersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplicateResponse) ProtoMessage() {}
+func (*PurgeDeadLettersResponse) ProtoMessage() {}
 
-func (x *ReplicateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[9]
-	if protoimpl.UnsafeEnabled && x != nil {
+func (x *PurgeDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_filecopier_proto_msgTypes[71]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -671,33 +5045,37 @@ func (x *ReplicateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplicateResponse.ProtoReflect.Descriptor instead.
-func (*ReplicateResponse) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use PurgeDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*PurgeDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_filecopier_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *ReplicateResponse) GetServers() int32 {
+func (x *PurgeDeadLettersResponse) GetPurged() int32 {
 	if x != nil {
-		return x.Servers
+		return x.Purged
 	}
 	return 0
 }
 
 type CallbackRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Key int64 `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Key              int64                  `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	Id               string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Status           CopyStatus             `protobuf:"varint,3,opt,name=status,proto3,enum=filecopier.CopyStatus" json:"status,omitempty"`
+	Error            string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	ErrorCode        int32                  `protobuf:"varint,5,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	BytesTransferred int64                  `protobuf:"varint,6,opt,name=bytes_transferred,json=bytesTransferred,proto3" json:"bytes_transferred,omitempty"`
+	MillisInQueue    int64                  `protobuf:"varint,7,opt,name=millis_in_queue,json=millisInQueue,proto3" json:"millis_in_queue,omitempty"`
+	MillisToCopy     int64                  `protobuf:"varint,8,opt,name=millis_to_copy,json=millisToCopy,proto3" json:"millis_to_copy,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *CallbackRequest) Reset() {
 	*x = CallbackRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[10]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_filecopier_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CallbackRequest) String() string {
@@ -707,8 +5085,8 @@ func (x *CallbackRequest) String() string {
 func (*CallbackRequest) ProtoMessage() {}
 
 func (x *CallbackRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[10]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_filecopier_proto_msgTypes[72]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -720,7 +5098,7 @@ func (x *CallbackRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CallbackRequest.ProtoReflect.Descriptor instead.
 func (*CallbackRequest) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{10}
+	return file_filecopier_proto_rawDescGZIP(), []int{72}
 }
 
 func (x *CallbackRequest) GetKey() int64 {
@@ -730,19 +5108,66 @@ func (x *CallbackRequest) GetKey() int64 {
 	return 0
 }
 
+func (x *CallbackRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CallbackRequest) GetStatus() CopyStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CopyStatus_UNKNOWN
+}
+
+func (x *CallbackRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CallbackRequest) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *CallbackRequest) GetBytesTransferred() int64 {
+	if x != nil {
+		return x.BytesTransferred
+	}
+	return 0
+}
+
+func (x *CallbackRequest) GetMillisInQueue() int64 {
+	if x != nil {
+		return x.MillisInQueue
+	}
+	return 0
+}
+
+func (x *CallbackRequest) GetMillisToCopy() int64 {
+	if x != nil {
+		return x.MillisToCopy
+	}
+	return 0
+}
+
 type CallbackResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CallbackResponse) Reset() {
 	*x = CallbackResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_filecopier_proto_msgTypes[11]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
+	mi := &file_filecopier_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
 func (x *CallbackResponse) String() string {
@@ -752,8 +5177,8 @@ func (x *CallbackResponse) String() string {
 func (*CallbackResponse) ProtoMessage() {}
 
 func (x *CallbackResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_filecopier_proto_msgTypes[11]
-	if protoimpl.UnsafeEnabled && x != nil {
+	mi := &file_filecopier_proto_msgTypes[73]
+	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -765,174 +5190,689 @@ func (x *CallbackResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CallbackResponse.ProtoReflect.Descriptor instead.
 func (*CallbackResponse) Descriptor() ([]byte, []int) {
-	return file_filecopier_proto_rawDescGZIP(), []int{11}
+	return file_filecopier_proto_rawDescGZIP(), []int{73}
 }
 
 var File_filecopier_proto protoreflect.FileDescriptor
 
-var file_filecopier_proto_rawDesc = []byte{
-	0x0a, 0x10, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x12, 0x0a, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x22, 0xfb,
-	0x01, 0x0a, 0x0b, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d,
-	0x0a, 0x0a, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x21, 0x0a,
-	0x0c, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x46, 0x69, 0x6c,
-	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
-	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69,
-	0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69,
-	0x74, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
-	0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x18, 0x08, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x08, 0x6f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x22, 0x99, 0x02, 0x0a,
-	0x0c, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a,
-	0x0e, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x5f, 0x74, 0x6f, 0x5f, 0x63, 0x6f, 0x70, 0x79, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x69, 0x6c, 0x6c, 0x69, 0x73, 0x54, 0x6f, 0x43,
-	0x6f, 0x70, 0x79, 0x12, 0x2e, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72,
-	0x2e, 0x43, 0x6f, 0x70, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x22, 0x0a, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x5f, 0x71,
-	0x75, 0x65, 0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65,
-	0x49, 0x6e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x24, 0x0a,
-	0x0e, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x5f, 0x69, 0x6e, 0x5f, 0x71, 0x75, 0x65, 0x75, 0x65, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x49, 0x6e, 0x51, 0x75,
-	0x65, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18,
-	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12,
-	0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x18,
-	0x0a, 0x07, 0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x07, 0x72, 0x65, 0x70, 0x65, 0x61, 0x74, 0x73, 0x22, 0x36, 0x0a, 0x0a, 0x4b, 0x65, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76,
-	0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72,
-	0x22, 0x23, 0x0a, 0x0b, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x14, 0x0a, 0x05, 0x6d, 0x79, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x6d, 0x79, 0x6b, 0x65, 0x79, 0x22, 0x3a, 0x0a, 0x0e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65,
-	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12,
-	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
-	0x79, 0x22, 0x3d, 0x0a, 0x0f, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x22, 0x23, 0x0a, 0x0d, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x28, 0x0a, 0x0e, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x78, 0x69, 0x73, 0x74,
-	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x65, 0x78, 0x69, 0x73, 0x74, 0x73, 0x22,
-	0x26, 0x0a, 0x10, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x2d, 0x0a, 0x11, 0x52, 0x65, 0x70, 0x6c, 0x69,
-	0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73,
-	0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x22, 0x23, 0x0a, 0x0f, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61,
-	0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x12, 0x0a, 0x10, 0x43,
-	0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2a,
-	0x46, 0x0a, 0x0a, 0x43, 0x6f, 0x70, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b, 0x0a,
-	0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x4e,
-	0x5f, 0x51, 0x55, 0x45, 0x55, 0x45, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x49, 0x4e, 0x5f, 0x50,
-	0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x43, 0x4f, 0x4d,
-	0x50, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x03, 0x32, 0xe8, 0x03, 0x0a, 0x11, 0x46, 0x69, 0x6c, 0x65,
-	0x43, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e, 0x0a,
-	0x07, 0x44, 0x69, 0x72, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63,
-	0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x18, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x43,
-	0x6f, 0x70, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x40, 0x0a,
-	0x09, 0x51, 0x75, 0x65, 0x75, 0x65, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x17, 0x2e, 0x66, 0x69, 0x6c,
-	0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72,
-	0x2e, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x3b, 0x0a, 0x04, 0x43, 0x6f, 0x70, 0x79, 0x12, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f,
-	0x70, 0x69, 0x65, 0x72, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x18, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x43, 0x6f,
-	0x70, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x3f, 0x0a, 0x0a,
-	0x52, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x2e, 0x66, 0x69, 0x6c,
-	0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x17, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e,
-	0x4b, 0x65, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x44, 0x0a,
-	0x07, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x73, 0x12, 0x1a, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63,
-	0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x73, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65,
-	0x72, 0x2e, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x06, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x12, 0x19, 0x2e,
-	0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x69, 0x73, 0x74,
-	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63,
-	0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x45, 0x78, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x09, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63,
-	0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72,
-	0x2e, 0x52, 0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1d, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x52,
-	0x65, 0x70, 0x6c, 0x69, 0x63, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x22, 0x00, 0x32, 0x5d, 0x0a, 0x12, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x6f, 0x70, 0x69, 0x65, 0x72,
-	0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x47, 0x0a, 0x08, 0x43, 0x61, 0x6c, 0x6c,
-	0x62, 0x61, 0x63, 0x6b, 0x12, 0x1b, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65,
-	0x72, 0x2e, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1c, 0x2e, 0x66, 0x69, 0x6c, 0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2e, 0x43,
-	0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x42, 0x2a, 0x5a, 0x28, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x62, 0x72, 0x6f, 0x74, 0x68, 0x65, 0x72, 0x6c, 0x6f, 0x67, 0x69, 0x63, 0x2f, 0x66, 0x69, 0x6c,
-	0x65, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
+const file_filecopier_proto_rawDesc = "" +
+	"\n" +
+	"\x10filecopier.proto\x12\n" +
+	"filecopier\"\xb7\a\n" +
+	"\vCopyRequest\x12\x1d\n" +
+	"\n" +
+	"input_file\x18\x01 \x01(\tR\tinputFile\x12!\n" +
+	"\finput_server\x18\x02 \x01(\tR\vinputServer\x12\x1f\n" +
+	"\voutput_file\x18\x03 \x01(\tR\n" +
+	"outputFile\x12#\n" +
+	"\routput_server\x18\x04 \x01(\tR\foutputServer\x12\x1a\n" +
+	"\bpriority\x18\x05 \x01(\x05R\bpriority\x12\x10\n" +
+	"\x03key\x18\x06 \x01(\x03R\x03key\x12\x1a\n" +
+	"\bcallback\x18\a \x01(\tR\bcallback\x12\x1a\n" +
+	"\boverride\x18\b \x01(\bR\boverride\x127\n" +
+	"\ttransport\x18\t \x01(\x0e2\x19.filecopier.TransportTypeR\ttransport\x12H\n" +
+	"\x12checksum_algorithm\x18\n" +
+	" \x01(\x0e2\x19.filecopier.HashAlgorithmR\x11checksumAlgorithm\x12\x1f\n" +
+	"\vskip_verify\x18\v \x01(\bR\n" +
+	"skipVerify\x12:\n" +
+	"\fretry_policy\x18\f \x01(\v2\x17.filecopier.RetryPolicyR\vretryPolicy\x12\x18\n" +
+	"\ainclude\x18\r \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\x0e \x03(\tR\aexclude\x12\x16\n" +
+	"\x06mirror\x18\x0f \x01(\bR\x06mirror\x12\x1b\n" +
+	"\ttrash_dir\x18\x10 \x01(\tR\btrashDir\x12\x17\n" +
+	"\adry_run\x18\x11 \x01(\bR\x06dryRun\x126\n" +
+	"\bmetadata\x18\x12 \x01(\v2\x1a.filecopier.MetadataPolicyR\bmetadata\x125\n" +
+	"\bsymlinks\x18\x13 \x01(\x0e2\x19.filecopier.SymlinkPolicyR\bsymlinks\x12B\n" +
+	"\rspecial_files\x18\x14 \x01(\x0e2\x1d.filecopier.SpecialFilePolicyR\fspecialFiles\x12-\n" +
+	"\x12preserve_hardlinks\x18\x15 \x01(\bR\x11preserveHardlinks\x12=\n" +
+	"\rtransfer_mode\x18\x16 \x01(\x0e2\x18.filecopier.TransferModeR\ftransferMode\x127\n" +
+	"\tcondition\x18\x17 \x01(\x0e2\x19.filecopier.CopyConditionR\tcondition\"\xcb\x02\n" +
+	"\x0eMetadataPolicy\x12#\n" +
+	"\rpreserve_mode\x18\x01 \x01(\bR\fpreserveMode\x12%\n" +
+	"\x0epreserve_owner\x18\x02 \x01(\bR\rpreserveOwner\x12%\n" +
+	"\x0epreserve_group\x18\x03 \x01(\bR\rpreserveGroup\x12%\n" +
+	"\x0epreserve_mtime\x18\x04 \x01(\bR\rpreserveMtime\x12%\n" +
+	"\x0epreserve_atime\x18\x05 \x01(\bR\rpreserveAtime\x12'\n" +
+	"\x0fpreserve_xattrs\x18\x06 \x01(\bR\x0epreserveXattrs\x12#\n" +
+	"\rpreserve_acls\x18\a \x01(\bR\fpreserveAcls\x12*\n" +
+	"\x03set\x18\b \x01(\v2\x18.filecopier.FileMetadataR\x03set\"\xce\x02\n" +
+	"\fFileMetadata\x12\x19\n" +
+	"\bhas_mode\x18\x01 \x01(\bR\ahasMode\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\rR\x04mode\x12\x14\n" +
+	"\x05owner\x18\x03 \x01(\tR\x05owner\x12\x14\n" +
+	"\x05group\x18\x04 \x01(\tR\x05group\x12\x14\n" +
+	"\x05mtime\x18\x05 \x01(\x03R\x05mtime\x12\x14\n" +
+	"\x05atime\x18\x06 \x01(\x03R\x05atime\x12<\n" +
+	"\x06xattrs\x18\a \x03(\v2$.filecopier.FileMetadata.XattrsEntryR\x06xattrs\x12\x1d\n" +
+	"\n" +
+	"access_acl\x18\b \x01(\fR\taccessAcl\x12\x1f\n" +
+	"\vdefault_acl\x18\t \x01(\fR\n" +
+	"defaultAcl\x1a9\n" +
+	"\vXattrsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value:\x028\x01\"\xc5\x01\n" +
+	"\vRetryPolicy\x12!\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05R\vmaxAttempts\x12,\n" +
+	"\x12initial_backoff_ms\x18\x02 \x01(\x03R\x10initialBackoffMs\x12$\n" +
+	"\x0emax_backoff_ms\x18\x03 \x01(\x03R\fmaxBackoffMs\x12\x16\n" +
+	"\x06jitter\x18\x04 \x01(\x01R\x06jitter\x12'\n" +
+	"\x0fretryable_codes\x18\x05 \x03(\x05R\x0eretryableCodes\"\xb4\x06\n" +
+	"\fCopyResponse\x12$\n" +
+	"\x0emillis_to_copy\x18\x01 \x01(\x03R\fmillisToCopy\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.filecopier.CopyStatusR\x06status\x12\"\n" +
+	"\rtime_in_queue\x18\x03 \x01(\x03R\vtimeInQueue\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12$\n" +
+	"\x0eindex_in_queue\x18\x05 \x01(\x05R\findexInQueue\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\x05R\bpriority\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\a \x01(\x05R\terrorCode\x12\x18\n" +
+	"\arepeats\x18\b \x01(\x05R\arepeats\x12\x1a\n" +
+	"\bchecksum\x18\t \x01(\tR\bchecksum\x12\x0e\n" +
+	"\x02id\x18\n" +
+	" \x01(\tR\x02id\x12*\n" +
+	"\x11next_attempt_time\x18\v \x01(\x03R\x0fnextAttemptTime\x12+\n" +
+	"\x11bytes_transferred\x18\f \x01(\x03R\x10bytesTransferred\x12\x1d\n" +
+	"\n" +
+	"bytes_done\x18\r \x01(\x03R\tbytesDone\x12\x1f\n" +
+	"\vbytes_total\x18\x0e \x01(\x03R\n" +
+	"bytesTotal\x12(\n" +
+	"\x10bytes_per_second\x18\x0f \x01(\x01R\x0ebytesPerSecond\x12\x1d\n" +
+	"\n" +
+	"eta_millis\x18\x10 \x01(\x03R\tetaMillis\x12#\n" +
+	"\rfiles_skipped\x18\x11 \x01(\x05R\ffilesSkipped\x12\x1f\n" +
+	"\vfiles_added\x18\x12 \x01(\x05R\n" +
+	"filesAdded\x12#\n" +
+	"\rfiles_updated\x18\x13 \x01(\x05R\ffilesUpdated\x12#\n" +
+	"\rfiles_deleted\x18\x14 \x01(\x05R\ffilesDeleted\x12$\n" +
+	"\x04plan\x18\x15 \x01(\v2\x10.filecopier.PlanR\x04plan\x124\n" +
+	"\bmetadata\x18\x16 \x01(\v2\x18.filecopier.FileMetadataR\bmetadata\x12\x1f\n" +
+	"\vbytes_saved\x18\x17 \x01(\x03R\n" +
+	"bytesSaved\"\xdb\x02\n" +
+	"\x10PlannedOperation\x12;\n" +
+	"\x06action\x18\x01 \x01(\x0e2#.filecopier.PlannedOperation.ActionR\x06action\x12!\n" +
+	"\finput_server\x18\x02 \x01(\tR\vinputServer\x12\x1d\n" +
+	"\n" +
+	"input_file\x18\x03 \x01(\tR\tinputFile\x12#\n" +
+	"\routput_server\x18\x04 \x01(\tR\foutputServer\x12\x1f\n" +
+	"\voutput_file\x18\x05 \x01(\tR\n" +
+	"outputFile\x12\x12\n" +
+	"\x04size\x18\x06 \x01(\x03R\x04size\x12\x1e\n" +
+	"\n" +
+	"overwrites\x18\a \x01(\bR\n" +
+	"overwrites\"N\n" +
+	"\x06Action\x12\b\n" +
+	"\x04COPY\x10\x00\x12\f\n" +
+	"\bMAKE_DIR\x10\x01\x12\n" +
+	"\n" +
+	"\x06REMOVE\x10\x02\x12\t\n" +
+	"\x05TRASH\x10\x03\x12\b\n" +
+	"\x04LINK\x10\x04\x12\v\n" +
+	"\aSYMLINK\x10\x05\"\x90\x01\n" +
+	"\x04Plan\x12<\n" +
+	"\n" +
+	"operations\x18\x01 \x03(\v2\x1c.filecopier.PlannedOperationR\n" +
+	"operations\x12\x18\n" +
+	"\aservers\x18\x02 \x03(\tR\aservers\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x03R\x05bytes\x12\x1a\n" +
+	"\bproblems\x18\x04 \x03(\tR\bproblems\"6\n" +
+	"\n" +
+	"KeyRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06server\x18\x02 \x01(\tR\x06server\"#\n" +
+	"\vKeyResponse\x12\x14\n" +
+	"\x05mykey\x18\x01 \x01(\tR\x05mykey\":\n" +
+	"\x0eAcceptsRequest\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"=\n" +
+	"\x0fAcceptsResponse\x12\x16\n" +
+	"\x06server\x18\x01 \x03(\tR\x06server\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\"#\n" +
+	"\rExistsRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"(\n" +
+	"\x0eExistsResponse\x12\x16\n" +
+	"\x06exists\x18\x02 \x01(\bR\x06exists\"?\n" +
+	"\x10ReplicateRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x17\n" +
+	"\adry_run\x18\x02 \x01(\bR\x06dryRun\"S\n" +
+	"\x11ReplicateResponse\x12\x18\n" +
+	"\aservers\x18\x01 \x01(\x05R\aservers\x12$\n" +
+	"\x04plan\x18\x02 \x01(\v2\x10.filecopier.PlanR\x04plan\"!\n" +
+	"\vStatRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\xef\x01\n" +
+	"\fStatResponse\x12\x16\n" +
+	"\x06exists\x18\x01 \x01(\bR\x06exists\x12\x15\n" +
+	"\x06is_dir\x18\x02 \x01(\bR\x05isDir\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04mode\x18\x04 \x01(\rR\x04mode\x12\x1a\n" +
+	"\bwritable\x18\x05 \x01(\bR\bwritable\x12\x1d\n" +
+	"\n" +
+	"free_bytes\x18\x06 \x01(\x03R\tfreeBytes\x12\x18\n" +
+	"\asymlink\x18\a \x01(\tR\asymlink\x12\x19\n" +
+	"\bmod_time\x18\b \x01(\x03R\amodTime\x12\x18\n" +
+	"\aspecial\x18\t \x01(\bR\aspecial\"\\\n" +
+	"\x12GetMetadataRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x122\n" +
+	"\x06policy\x18\x02 \x01(\v2\x1a.filecopier.MetadataPolicyR\x06policy\"K\n" +
+	"\x13GetMetadataResponse\x124\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x18.filecopier.FileMetadataR\bmetadata\"^\n" +
+	"\x12SetMetadataRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x124\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x18.filecopier.FileMetadataR\bmetadata\"K\n" +
+	"\x13SetMetadataResponse\x124\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x18.filecopier.FileMetadataR\bmetadata\"\x8e\x01\n" +
+	"\tFileChunk\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04mode\x18\x04 \x01(\rR\x04mode\x12\x19\n" +
+	"\bmod_time\x18\x05 \x01(\x03R\amodTime\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x03R\x06offset\"7\n" +
+	"\x10PushFileResponse\x12#\n" +
+	"\rbytes_written\x18\x01 \x01(\x03R\fbytesWritten\"l\n" +
+	"\x0fPullFileRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x19\n" +
+	"\bmod_time\x18\x04 \x01(\x03R\amodTime\"l\n" +
+	"\x0fTransferJournal\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x19\n" +
+	"\bmod_time\x18\x04 \x01(\x03R\amodTime\"#\n" +
+	"\rResumeRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"^\n" +
+	"\x0fChecksumRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x127\n" +
+	"\talgorithm\x18\x02 \x01(\x0e2\x19.filecopier.HashAlgorithmR\talgorithm\"B\n" +
+	"\x10ChecksumResponse\x12\x1a\n" +
+	"\bchecksum\x18\x01 \x01(\tR\bchecksum\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"3\n" +
+	"\rRenameRequest\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\"\x10\n" +
+	"\x0eRenameResponse\"#\n" +
+	"\rRemoveRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\"\x10\n" +
+	"\x0eRemoveResponse\"\x94\x01\n" +
+	"\tFileEntry\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x15\n" +
+	"\x06is_dir\x18\x02 \x01(\bR\x05isDir\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04mode\x18\x04 \x01(\rR\x04mode\x12\x18\n" +
+	"\asymlink\x18\x05 \x01(\tR\asymlink\x12\x1a\n" +
+	"\bhardlink\x18\x06 \x01(\tR\bhardlink\"\x82\x02\n" +
+	"\x0eListDirRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x18\n" +
+	"\ainclude\x18\x02 \x03(\tR\ainclude\x12\x18\n" +
+	"\aexclude\x18\x03 \x03(\tR\aexclude\x125\n" +
+	"\bsymlinks\x18\x04 \x01(\x0e2\x19.filecopier.SymlinkPolicyR\bsymlinks\x12B\n" +
+	"\rspecial_files\x18\x05 \x01(\x0e2\x1d.filecopier.SpecialFilePolicyR\fspecialFiles\x12-\n" +
+	"\x12preserve_hardlinks\x18\x06 \x01(\bR\x11preserveHardlinks\"\\\n" +
+	"\x0fListDirResponse\x12/\n" +
+	"\aentries\x18\x01 \x03(\v2\x15.filecopier.FileEntryR\aentries\x12\x18\n" +
+	"\askipped\x18\x02 \x01(\x05R\askipped\"E\n" +
+	"\x10SignatureRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"block_size\x18\x02 \x01(\x05R\tblockSize\"<\n" +
+	"\x0eBlockSignature\x12\x12\n" +
+	"\x04weak\x18\x01 \x01(\rR\x04weak\x12\x16\n" +
+	"\x06strong\x18\x02 \x01(\fR\x06strong\"r\n" +
+	"\tSignature\x12\x1d\n" +
+	"\n" +
+	"block_size\x18\x01 \x01(\x05R\tblockSize\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x122\n" +
+	"\x06blocks\x18\x03 \x03(\v2\x1a.filecopier.BlockSignatureR\x06blocks\"O\n" +
+	"\fDeltaRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12+\n" +
+	"\x05basis\x18\x02 \x01(\v2\x15.filecopier.SignatureR\x05basis\"\xe0\x01\n" +
+	"\aDeltaOp\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\x12\x1d\n" +
+	"\n" +
+	"block_size\x18\x02 \x01(\x05R\tblockSize\x12\x1d\n" +
+	"\n" +
+	"basis_path\x18\x03 \x01(\tR\tbasisPath\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x12\x14\n" +
+	"\x05block\x18\x05 \x01(\x03R\x05block\x12\x16\n" +
+	"\x06blocks\x18\x06 \x01(\x03R\x06blocks\x12\x12\n" +
+	"\x04data\x18\a \x01(\fR\x04data\x12\x12\n" +
+	"\x04mode\x18\b \x01(\rR\x04mode\x12\x19\n" +
+	"\bmod_time\x18\t \x01(\x03R\amodTime\"@\n" +
+	"\x12ApplyDeltaResponse\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\x03R\x04size\x12\x16\n" +
+	"\x06reused\x18\x02 \x01(\x03R\x06reused\"8\n" +
+	"\x0eMakeDirRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\rR\x04mode\"\x11\n" +
+	"\x0fMakeDirResponse\"9\n" +
+	"\vLinkRequest\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"\x0e\n" +
+	"\fLinkResponse\"<\n" +
+	"\x0eSymlinkRequest\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"\x11\n" +
+	"\x0fSymlinkResponse\"\xcd\x05\n" +
+	"\x06DirJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12)\n" +
+	"\x03req\x18\x02 \x01(\v2\x17.filecopier.CopyRequestR\x03req\x12.\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x16.filecopier.CopyStatusR\x06status\x12)\n" +
+	"\x04dirs\x18\x04 \x03(\v2\x15.filecopier.FileEntryR\x04dirs\x12/\n" +
+	"\apending\x18\x05 \x03(\v2\x15.filecopier.FileEntryR\apending\x12\x16\n" +
+	"\x06active\x18\x06 \x03(\tR\x06active\x12\x1f\n" +
+	"\vfiles_total\x18\a \x01(\x05R\n" +
+	"filesTotal\x12%\n" +
+	"\x0efiles_complete\x18\b \x01(\x05R\rfilesComplete\x12!\n" +
+	"\ffiles_failed\x18\t \x01(\x05R\vfilesFailed\x12+\n" +
+	"\x11bytes_transferred\x18\n" +
+	" \x01(\x03R\x10bytesTransferred\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"time_added\x18\f \x01(\x03R\ttimeAdded\x12#\n" +
+	"\rtime_finished\x18\r \x01(\x03R\ftimeFinished\x12#\n" +
+	"\rfiles_skipped\x18\x0e \x01(\x05R\ffilesSkipped\x125\n" +
+	"\n" +
+	"extraneous\x18\x0f \x03(\v2\x15.filecopier.FileEntryR\n" +
+	"extraneous\x12\x1f\n" +
+	"\vfiles_added\x18\x10 \x01(\x05R\n" +
+	"filesAdded\x12#\n" +
+	"\rfiles_updated\x18\x11 \x01(\x05R\ffilesUpdated\x12#\n" +
+	"\rfiles_deleted\x18\x12 \x01(\x05R\ffilesDeleted\x12+\n" +
+	"\x05links\x18\x13 \x03(\v2\x15.filecopier.FileEntryR\x05links\"1\n" +
+	"\aDirJobs\x12&\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x12.filecopier.DirJobR\x04jobs\"6\n" +
+	"\bTempFile\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\"7\n" +
+	"\tTempFiles\x12*\n" +
+	"\x05files\x18\x01 \x03(\v2\x14.filecopier.TempFileR\x05files\"\xa0\x02\n" +
+	"\n" +
+	"QueueEntry\x12)\n" +
+	"\x03req\x18\x01 \x01(\v2\x17.filecopier.CopyRequestR\x03req\x12,\n" +
+	"\x04resp\x18\x02 \x01(\v2\x18.filecopier.CopyResponseR\x04resp\x12\x1d\n" +
+	"\n" +
+	"time_added\x18\x03 \x01(\x03R\ttimeAdded\x12\x18\n" +
+	"\aremoved\x18\x04 \x01(\bR\aremoved\x12!\n" +
+	"\ftime_started\x18\x05 \x01(\x03R\vtimeStarted\x12#\n" +
+	"\rtime_finished\x18\x06 \x01(\x03R\ftimeFinished\x128\n" +
+	"\bcallback\x18\a \x01(\v2\x1c.filecopier.CallbackDeliveryR\bcallback\"\xa0\x02\n" +
+	"\x10CallbackDelivery\x12\x16\n" +
+	"\x06server\x18\x01 \x01(\tR\x06server\x125\n" +
+	"\arequest\x18\x02 \x01(\v2\x1b.filecopier.CallbackRequestR\arequest\x12/\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x19.filecopier.CallbackStateR\x05state\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12*\n" +
+	"\x11next_attempt_time\x18\x05 \x01(\x03R\x0fnextAttemptTime\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x12%\n" +
+	"\x0edelivered_time\x18\a \x01(\x03R\rdeliveredTime\"N\n" +
+	"\x0eCallbackOutbox\x12<\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1c.filecopier.CallbackDeliveryR\n" +
+	"deliveries\"5\n" +
+	"\x11CopyStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\x03R\x03key\"4\n" +
+	"\x10WatchCopyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\x03R\x03key\"\xf2\x01\n" +
+	"\tCopyEvent\x12,\n" +
+	"\x05entry\x18\x01 \x01(\v2\x16.filecopier.QueueEntryR\x05entry\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.filecopier.CopyStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"bytes_done\x18\x03 \x01(\x03R\tbytesDone\x12\x1f\n" +
+	"\vbytes_total\x18\x04 \x01(\x03R\n" +
+	"bytesTotal\x12(\n" +
+	"\x10bytes_per_second\x18\x05 \x01(\x01R\x0ebytesPerSecond\x12\x1d\n" +
+	"\n" +
+	"eta_millis\x18\x06 \x01(\x03R\tetaMillis\"h\n" +
+	"\x12CopyStatusResponse\x12,\n" +
+	"\x05entry\x18\x01 \x01(\v2\x16.filecopier.QueueEntryR\x05entry\x12$\n" +
+	"\x03job\x18\x02 \x01(\v2\x12.filecopier.DirJobR\x03job\"\x9c\x01\n" +
+	"\x10ListQueueRequest\x12.\n" +
+	"\x06status\x18\x01 \x03(\x0e2\x16.filecopier.CopyStatusR\x06status\x12!\n" +
+	"\finput_server\x18\x02 \x01(\tR\vinputServer\x12#\n" +
+	"\routput_server\x18\x03 \x01(\tR\foutputServer\x12\x10\n" +
+	"\x03key\x18\x04 \x01(\x03R\x03key\"]\n" +
+	"\x11ListQueueResponse\x120\n" +
+	"\aentries\x18\x01 \x03(\v2\x16.filecopier.QueueEntryR\aentries\x12\x16\n" +
+	"\x06paused\x18\x02 \x01(\bR\x06paused\"5\n" +
+	"\x11CancelCopyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\x03R\x03key\"B\n" +
+	"\x12CancelCopyResponse\x12,\n" +
+	"\x05entry\x18\x01 \x01(\v2\x16.filecopier.QueueEntryR\x05entry\"\x13\n" +
+	"\x11PauseQueueRequest\"\x14\n" +
+	"\x12PauseQueueResponse\"\x14\n" +
+	"\x12ResumeQueueRequest\"\x15\n" +
+	"\x13ResumeQueueResponse\"\x18\n" +
+	"\x16ListDeadLettersRequest\"K\n" +
+	"\x17ListDeadLettersResponse\x120\n" +
+	"\aentries\x18\x01 \x03(\v2\x16.filecopier.QueueEntryR\aentries\"*\n" +
+	"\x18RequeueDeadLetterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"Q\n" +
+	"\x19RequeueDeadLetterResponse\x124\n" +
+	"\bresponse\x18\x01 \x01(\v2\x18.filecopier.CopyResponseR\bresponse\"=\n" +
+	"\x17PurgeDeadLettersRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\"2\n" +
+	"\x18PurgeDeadLettersResponse\x12\x16\n" +
+	"\x06purged\x18\x01 \x01(\x05R\x06purged\"\x93\x02\n" +
+	"\x0fCallbackRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12.\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x16.filecopier.CopyStatusR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x05 \x01(\x05R\terrorCode\x12+\n" +
+	"\x11bytes_transferred\x18\x06 \x01(\x03R\x10bytesTransferred\x12&\n" +
+	"\x0fmillis_in_queue\x18\a \x01(\x03R\rmillisInQueue\x12$\n" +
+	"\x0emillis_to_copy\x18\b \x01(\x03R\fmillisToCopy\"\x12\n" +
+	"\x10CallbackResponse*\x94\x01\n" +
+	"\n" +
+	"CopyStatus\x12\v\n" +
+	"\aUNKNOWN\x10\x00\x12\f\n" +
+	"\bIN_QUEUE\x10\x01\x12\x0f\n" +
+	"\vIN_PROGRESS\x10\x02\x12\f\n" +
+	"\bCOMPLETE\x10\x03\x12\x11\n" +
+	"\rVERIFY_FAILED\x10\x04\x12\r\n" +
+	"\tCANCELLED\x10\x05\x12\x11\n" +
+	"\rDEAD_LETTERED\x10\x06\x12\n" +
+	"\n" +
+	"\x06FAILED\x10\a\x12\v\n" +
+	"\aSKIPPED\x10\b*q\n" +
+	"\rCopyCondition\x12\x0f\n" +
+	"\vALWAYS_COPY\x10\x00\x12\x0e\n" +
+	"\n" +
+	"IF_MISSING\x10\x01\x12\f\n" +
+	"\bIF_NEWER\x10\x02\x12\x1c\n" +
+	"\x18IF_SIZE_OR_MTIME_DIFFERS\x10\x03\x12\x13\n" +
+	"\x0fIF_HASH_DIFFERS\x10\x04*V\n" +
+	"\rTransportType\x12\x15\n" +
+	"\x11DEFAULT_TRANSPORT\x10\x00\x12\a\n" +
+	"\x03SCP\x10\x01\x12\t\n" +
+	"\x05RSYNC\x10\x02\x12\t\n" +
+	"\x05LOCAL\x10\x03\x12\x0f\n" +
+	"\vGRPC_STREAM\x10\x04*J\n" +
+	"\rSymlinkPolicy\x12\x13\n" +
+	"\x0fFOLLOW_SYMLINKS\x10\x00\x12\x11\n" +
+	"\rCOPY_SYMLINKS\x10\x01\x12\x11\n" +
+	"\rSKIP_SYMLINKS\x10\x02*F\n" +
+	"\x11SpecialFilePolicy\x12\x16\n" +
+	"\x12SKIP_SPECIAL_FILES\x10\x00\x12\x19\n" +
+	"\x15FAIL_ON_SPECIAL_FILES\x10\x01*5\n" +
+	"\fTransferMode\x12\x11\n" +
+	"\rFULL_TRANSFER\x10\x00\x12\x12\n" +
+	"\x0eDELTA_TRANSFER\x10\x01*)\n" +
+	"\rHashAlgorithm\x12\n" +
+	"\n" +
+	"\x06SHA256\x10\x00\x12\f\n" +
+	"\bXXHASH64\x10\x01*N\n" +
+	"\rCallbackState\x12\x0f\n" +
+	"\vNO_CALLBACK\x10\x00\x12\x14\n" +
+	"\x10CALLBACK_PENDING\x10\x01\x12\x16\n" +
+	"\x12CALLBACK_DELIVERED\x10\x022\xfb\x11\n" +
+	"\x11FileCopierService\x12<\n" +
+	"\aDirCopy\x12\x17.filecopier.CopyRequest\x1a\x18.filecopier.CopyResponse\x12>\n" +
+	"\tQueueCopy\x12\x17.filecopier.CopyRequest\x1a\x18.filecopier.CopyResponse\x129\n" +
+	"\x04Copy\x12\x17.filecopier.CopyRequest\x1a\x18.filecopier.CopyResponse\x12=\n" +
+	"\n" +
+	"ReceiveKey\x12\x16.filecopier.KeyRequest\x1a\x17.filecopier.KeyResponse\x12B\n" +
+	"\aAccepts\x12\x1a.filecopier.AcceptsRequest\x1a\x1b.filecopier.AcceptsResponse\x12?\n" +
+	"\x06Exists\x12\x19.filecopier.ExistsRequest\x1a\x1a.filecopier.ExistsResponse\x12H\n" +
+	"\tReplicate\x12\x1c.filecopier.ReplicateRequest\x1a\x1d.filecopier.ReplicateResponse\x12A\n" +
+	"\bPushFile\x12\x15.filecopier.FileChunk\x1a\x1c.filecopier.PushFileResponse(\x01\x12@\n" +
+	"\bPullFile\x12\x1b.filecopier.PullFileRequest\x1a\x15.filecopier.FileChunk0\x01\x12I\n" +
+	"\x0fGetResumeOffset\x12\x19.filecopier.ResumeRequest\x1a\x1b.filecopier.TransferJournal\x12E\n" +
+	"\bChecksum\x12\x1b.filecopier.ChecksumRequest\x1a\x1c.filecopier.ChecksumResponse\x12?\n" +
+	"\x06Rename\x12\x19.filecopier.RenameRequest\x1a\x1a.filecopier.RenameResponse\x12?\n" +
+	"\x06Remove\x12\x19.filecopier.RemoveRequest\x1a\x1a.filecopier.RemoveResponse\x12B\n" +
+	"\aListDir\x12\x1a.filecopier.ListDirRequest\x1a\x1b.filecopier.ListDirResponse\x12B\n" +
+	"\aMakeDir\x12\x1a.filecopier.MakeDirRequest\x1a\x1b.filecopier.MakeDirResponse\x129\n" +
+	"\x04Link\x12\x17.filecopier.LinkRequest\x1a\x18.filecopier.LinkResponse\x12B\n" +
+	"\aSymlink\x12\x1a.filecopier.SymlinkRequest\x1a\x1b.filecopier.SymlinkResponse\x12@\n" +
+	"\tSignature\x12\x1c.filecopier.SignatureRequest\x1a\x15.filecopier.Signature\x128\n" +
+	"\x05Delta\x12\x18.filecopier.DeltaRequest\x1a\x13.filecopier.DeltaOp0\x01\x12C\n" +
+	"\n" +
+	"ApplyDelta\x12\x13.filecopier.DeltaOp\x1a\x1e.filecopier.ApplyDeltaResponse(\x01\x129\n" +
+	"\x04Stat\x12\x17.filecopier.StatRequest\x1a\x18.filecopier.StatResponse\x12N\n" +
+	"\vGetMetadata\x12\x1e.filecopier.GetMetadataRequest\x1a\x1f.filecopier.GetMetadataResponse\x12N\n" +
+	"\vSetMetadata\x12\x1e.filecopier.SetMetadataRequest\x1a\x1f.filecopier.SetMetadataResponse\x12N\n" +
+	"\rGetCopyStatus\x12\x1d.filecopier.CopyStatusRequest\x1a\x1e.filecopier.CopyStatusResponse\x12B\n" +
+	"\tWatchCopy\x12\x1c.filecopier.WatchCopyRequest\x1a\x15.filecopier.CopyEvent0\x01\x12H\n" +
+	"\tListQueue\x12\x1c.filecopier.ListQueueRequest\x1a\x1d.filecopier.ListQueueResponse\x12K\n" +
+	"\n" +
+	"CancelCopy\x12\x1d.filecopier.CancelCopyRequest\x1a\x1e.filecopier.CancelCopyResponse\x12K\n" +
+	"\n" +
+	"PauseQueue\x12\x1d.filecopier.PauseQueueRequest\x1a\x1e.filecopier.PauseQueueResponse\x12N\n" +
+	"\vResumeQueue\x12\x1e.filecopier.ResumeQueueRequest\x1a\x1f.filecopier.ResumeQueueResponse\x12Z\n" +
+	"\x0fListDeadLetters\x12\".filecopier.ListDeadLettersRequest\x1a#.filecopier.ListDeadLettersResponse\x12`\n" +
+	"\x11RequeueDeadLetter\x12$.filecopier.RequeueDeadLetterRequest\x1a%.filecopier.RequeueDeadLetterResponse\x12]\n" +
+	"\x10PurgeDeadLetters\x12#.filecopier.PurgeDeadLettersRequest\x1a$.filecopier.PurgeDeadLettersResponse2[\n" +
+	"\x12FileCopierCallback\x12E\n" +
+	"\bCallback\x12\x1b.filecopier.CallbackRequest\x1a\x1c.filecopier.CallbackResponseB*Z(github.com/brotherlogic/filecopier/protob\x06proto3"
 
 var (
 	file_filecopier_proto_rawDescOnce sync.Once
-	file_filecopier_proto_rawDescData = file_filecopier_proto_rawDesc
+	file_filecopier_proto_rawDescData []byte
 )
 
 func file_filecopier_proto_rawDescGZIP() []byte {
 	file_filecopier_proto_rawDescOnce.Do(func() {
-		file_filecopier_proto_rawDescData = protoimpl.X.CompressGZIP(file_filecopier_proto_rawDescData)
+		file_filecopier_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_filecopier_proto_rawDesc), len(file_filecopier_proto_rawDesc)))
 	})
 	return file_filecopier_proto_rawDescData
 }
 
-var file_filecopier_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_filecopier_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
-var file_filecopier_proto_goTypes = []interface{}{
-	(CopyStatus)(0),           // 0: filecopier.CopyStatus
-	(*CopyRequest)(nil),       // 1: filecopier.CopyRequest
-	(*CopyResponse)(nil),      // 2: filecopier.CopyResponse
-	(*KeyRequest)(nil),        // 3: filecopier.KeyRequest
-	(*KeyResponse)(nil),       // 4: filecopier.KeyResponse
-	(*AcceptsRequest)(nil),    // 5: filecopier.AcceptsRequest
-	(*AcceptsResponse)(nil),   // 6: filecopier.AcceptsResponse
-	(*ExistsRequest)(nil),     // 7: filecopier.ExistsRequest
-	(*ExistsResponse)(nil),    // 8: filecopier.ExistsResponse
-	(*ReplicateRequest)(nil),  // 9: filecopier.ReplicateRequest
-	(*ReplicateResponse)(nil), // 10: filecopier.ReplicateResponse
-	(*CallbackRequest)(nil),   // 11: filecopier.CallbackRequest
-	(*CallbackResponse)(nil),  // 12: filecopier.CallbackResponse
+var file_filecopier_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_filecopier_proto_msgTypes = make([]protoimpl.MessageInfo, 75)
+var file_filecopier_proto_goTypes = []any{
+	(CopyStatus)(0),                   // 0: filecopier.CopyStatus
+	(CopyCondition)(0),                // 1: filecopier.CopyCondition
+	(TransportType)(0),                // 2: filecopier.TransportType
+	(SymlinkPolicy)(0),                // 3: filecopier.SymlinkPolicy
+	(SpecialFilePolicy)(0),            // 4: filecopier.SpecialFilePolicy
+	(TransferMode)(0),                 // 5: filecopier.TransferMode
+	(HashAlgorithm)(0),                // 6: filecopier.HashAlgorithm
+	(CallbackState)(0),                // 7: filecopier.CallbackState
+	(PlannedOperation_Action)(0),      // 8: filecopier.PlannedOperation.Action
+	(*CopyRequest)(nil),               // 9: filecopier.CopyRequest
+	(*MetadataPolicy)(nil),            // 10: filecopier.MetadataPolicy
+	(*FileMetadata)(nil),              // 11: filecopier.FileMetadata
+	(*RetryPolicy)(nil),               // 12: filecopier.RetryPolicy
+	(*CopyResponse)(nil),              // 13: filecopier.CopyResponse
+	(*PlannedOperation)(nil),          // 14: filecopier.PlannedOperation
+	(*Plan)(nil),                      // 15: filecopier.Plan
+	(*KeyRequest)(nil),                // 16: filecopier.KeyRequest
+	(*KeyResponse)(nil),               // 17: filecopier.KeyResponse
+	(*AcceptsRequest)(nil),            // 18: filecopier.AcceptsRequest
+	(*AcceptsResponse)(nil),           // 19: filecopier.AcceptsResponse
+	(*ExistsRequest)(nil),             // 20: filecopier.ExistsRequest
+	(*ExistsResponse)(nil),            // 21: filecopier.ExistsResponse
+	(*ReplicateRequest)(nil),          // 22: filecopier.ReplicateRequest
+	(*ReplicateResponse)(nil),         // 23: filecopier.ReplicateResponse
+	(*StatRequest)(nil),               // 24: filecopier.StatRequest
+	(*StatResponse)(nil),              // 25: filecopier.StatResponse
+	(*GetMetadataRequest)(nil),        // 26: filecopier.GetMetadataRequest
+	(*GetMetadataResponse)(nil),       // 27: filecopier.GetMetadataResponse
+	(*SetMetadataRequest)(nil),        // 28: filecopier.SetMetadataRequest
+	(*SetMetadataResponse)(nil),       // 29: filecopier.SetMetadataResponse
+	(*FileChunk)(nil),                 // 30: filecopier.FileChunk
+	(*PushFileResponse)(nil),          // 31: filecopier.PushFileResponse
+	(*PullFileRequest)(nil),           // 32: filecopier.PullFileRequest
+	(*TransferJournal)(nil),           // 33: filecopier.TransferJournal
+	(*ResumeRequest)(nil),             // 34: filecopier.ResumeRequest
+	(*ChecksumRequest)(nil),           // 35: filecopier.ChecksumRequest
+	(*ChecksumResponse)(nil),          // 36: filecopier.ChecksumResponse
+	(*RenameRequest)(nil),             // 37: filecopier.RenameRequest
+	(*RenameResponse)(nil),            // 38: filecopier.RenameResponse
+	(*RemoveRequest)(nil),             // 39: filecopier.RemoveRequest
+	(*RemoveResponse)(nil),            // 40: filecopier.RemoveResponse
+	(*FileEntry)(nil),                 // 41: filecopier.FileEntry
+	(*ListDirRequest)(nil),            // 42: filecopier.ListDirRequest
+	(*ListDirResponse)(nil),           // 43: filecopier.ListDirResponse
+	(*SignatureRequest)(nil),          // 44: filecopier.SignatureRequest
+	(*BlockSignature)(nil),            // 45: filecopier.BlockSignature
+	(*Signature)(nil),                 // 46: filecopier.Signature
+	(*DeltaRequest)(nil),              // 47: filecopier.DeltaRequest
+	(*DeltaOp)(nil),                   // 48: filecopier.DeltaOp
+	(*ApplyDeltaResponse)(nil),        // 49: filecopier.ApplyDeltaResponse
+	(*MakeDirRequest)(nil),            // 50: filecopier.MakeDirRequest
+	(*MakeDirResponse)(nil),           // 51: filecopier.MakeDirResponse
+	(*LinkRequest)(nil),               // 52: filecopier.LinkRequest
+	(*LinkResponse)(nil),              // 53: filecopier.LinkResponse
+	(*SymlinkRequest)(nil),            // 54: filecopier.SymlinkRequest
+	(*SymlinkResponse)(nil),           // 55: filecopier.SymlinkResponse
+	(*DirJob)(nil),                    // 56: filecopier.DirJob
+	(*DirJobs)(nil),                   // 57: filecopier.DirJobs
+	(*TempFile)(nil),                  // 58: filecopier.TempFile
+	(*TempFiles)(nil),                 // 59: filecopier.TempFiles
+	(*QueueEntry)(nil),                // 60: filecopier.QueueEntry
+	(*CallbackDelivery)(nil),          // 61: filecopier.CallbackDelivery
+	(*CallbackOutbox)(nil),            // 62: filecopier.CallbackOutbox
+	(*CopyStatusRequest)(nil),         // 63: filecopier.CopyStatusRequest
+	(*WatchCopyRequest)(nil),          // 64: filecopier.WatchCopyRequest
+	(*CopyEvent)(nil),                 // 65: filecopier.CopyEvent
+	(*CopyStatusResponse)(nil),        // 66: filecopier.CopyStatusResponse
+	(*ListQueueRequest)(nil),          // 67: filecopier.ListQueueRequest
+	(*ListQueueResponse)(nil),         // 68: filecopier.ListQueueResponse
+	(*CancelCopyRequest)(nil),         // 69: filecopier.CancelCopyRequest
+	(*CancelCopyResponse)(nil),        // 70: filecopier.CancelCopyResponse
+	(*PauseQueueRequest)(nil),         // 71: filecopier.PauseQueueRequest
+	(*PauseQueueResponse)(nil),        // 72: filecopier.PauseQueueResponse
+	(*ResumeQueueRequest)(nil),        // 73: filecopier.ResumeQueueRequest
+	(*ResumeQueueResponse)(nil),       // 74: filecopier.ResumeQueueResponse
+	(*ListDeadLettersRequest)(nil),    // 75: filecopier.ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),   // 76: filecopier.ListDeadLettersResponse
+	(*RequeueDeadLetterRequest)(nil),  // 77: filecopier.RequeueDeadLetterRequest
+	(*RequeueDeadLetterResponse)(nil), // 78: filecopier.RequeueDeadLetterResponse
+	(*PurgeDeadLettersRequest)(nil),   // 79: filecopier.PurgeDeadLettersRequest
+	(*PurgeDeadLettersResponse)(nil),  // 80: filecopier.PurgeDeadLettersResponse
+	(*CallbackRequest)(nil),           // 81: filecopier.CallbackRequest
+	(*CallbackResponse)(nil),          // 82: filecopier.CallbackResponse
+	nil,                               // 83: filecopier.FileMetadata.XattrsEntry
 }
 var file_filecopier_proto_depIdxs = []int32{
-	0,  // 0: filecopier.CopyResponse.status:type_name -> filecopier.CopyStatus
-	1,  // 1: filecopier.FileCopierService.DirCopy:input_type -> filecopier.CopyRequest
-	1,  // 2: filecopier.FileCopierService.QueueCopy:input_type -> filecopier.CopyRequest
-	1,  // 3: filecopier.FileCopierService.Copy:input_type -> filecopier.CopyRequest
-	3,  // 4: filecopier.FileCopierService.ReceiveKey:input_type -> filecopier.KeyRequest
-	5,  // 5: filecopier.FileCopierService.Accepts:input_type -> filecopier.AcceptsRequest
-	7,  // 6: filecopier.FileCopierService.Exists:input_type -> filecopier.ExistsRequest
-	9,  // 7: filecopier.FileCopierService.Replicate:input_type -> filecopier.ReplicateRequest
-	11, // 8: filecopier.FileCopierCallback.Callback:input_type -> filecopier.CallbackRequest
-	2,  // 9: filecopier.FileCopierService.DirCopy:output_type -> filecopier.CopyResponse
-	2,  // 10: filecopier.FileCopierService.QueueCopy:output_type -> filecopier.CopyResponse
-	2,  // 11: filecopier.FileCopierService.Copy:output_type -> filecopier.CopyResponse
-	4,  // 12: filecopier.FileCopierService.ReceiveKey:output_type -> filecopier.KeyResponse
-	6,  // 13: filecopier.FileCopierService.Accepts:output_type -> filecopier.AcceptsResponse
-	8,  // 14: filecopier.FileCopierService.Exists:output_type -> filecopier.ExistsResponse
-	10, // 15: filecopier.FileCopierService.Replicate:output_type -> filecopier.ReplicateResponse
-	12, // 16: filecopier.FileCopierCallback.Callback:output_type -> filecopier.CallbackResponse
-	9,  // [9:17] is the sub-list for method output_type
-	1,  // [1:9] is the sub-list for method input_type
-	1,  // [1:1] is the sub-list for extension type_name
-	1,  // [1:1] is the sub-list for extension extendee
-	0,  // [0:1] is the sub-list for field type_name
+	2,  // 0: filecopier.CopyRequest.transport:type_name -> filecopier.TransportType
+	6,  // 1: filecopier.CopyRequest.checksum_algorithm:type_name -> filecopier.HashAlgorithm
+	12, // 2: filecopier.CopyRequest.retry_policy:type_name -> filecopier.RetryPolicy
+	10, // 3: filecopier.CopyRequest.metadata:type_name -> filecopier.MetadataPolicy
+	3,  // 4: filecopier.CopyRequest.symlinks:type_name -> filecopier.SymlinkPolicy
+	4,  // 5: filecopier.CopyRequest.special_files:type_name -> filecopier.SpecialFilePolicy
+	5,  // 6: filecopier.CopyRequest.transfer_mode:type_name -> filecopier.TransferMode
+	1,  // 7: filecopier.CopyRequest.condition:type_name -> filecopier.CopyCondition
+	11, // 8: filecopier.MetadataPolicy.set:type_name -> filecopier.FileMetadata
+	83, // 9: filecopier.FileMetadata.xattrs:type_name -> filecopier.FileMetadata.XattrsEntry
+	0,  // 10: filecopier.CopyResponse.status:type_name -> filecopier.CopyStatus
+	15, // 11: filecopier.CopyResponse.plan:type_name -> filecopier.Plan
+	11, // 12: filecopier.CopyResponse.metadata:type_name -> filecopier.FileMetadata
+	8,  // 13: filecopier.PlannedOperation.action:type_name -> filecopier.PlannedOperation.Action
+	14, // 14: filecopier.Plan.operations:type_name -> filecopier.PlannedOperation
+	15, // 15: filecopier.ReplicateResponse.plan:type_name -> filecopier.Plan
+	10, // 16: filecopier.GetMetadataRequest.policy:type_name -> filecopier.MetadataPolicy
+	11, // 17: filecopier.GetMetadataResponse.metadata:type_name -> filecopier.FileMetadata
+	11, // 18: filecopier.SetMetadataRequest.metadata:type_name -> filecopier.FileMetadata
+	11, // 19: filecopier.SetMetadataResponse.metadata:type_name -> filecopier.FileMetadata
+	6,  // 20: filecopier.ChecksumRequest.algorithm:type_name -> filecopier.HashAlgorithm
+	3,  // 21: filecopier.ListDirRequest.symlinks:type_name -> filecopier.SymlinkPolicy
+	4,  // 22: filecopier.ListDirRequest.special_files:type_name -> filecopier.SpecialFilePolicy
+	41, // 23: filecopier.ListDirResponse.entries:type_name -> filecopier.FileEntry
+	45, // 24: filecopier.Signature.blocks:type_name -> filecopier.BlockSignature
+	46, // 25: filecopier.DeltaRequest.basis:type_name -> filecopier.Signature
+	9,  // 26: filecopier.DirJob.req:type_name -> filecopier.CopyRequest
+	0,  // 27: filecopier.DirJob.status:type_name -> filecopier.CopyStatus
+	41, // 28: filecopier.DirJob.dirs:type_name -> filecopier.FileEntry
+	41, // 29: filecopier.DirJob.pending:type_name -> filecopier.FileEntry
+	41, // 30: filecopier.DirJob.extraneous:type_name -> filecopier.FileEntry
+	41, // 31: filecopier.DirJob.links:type_name -> filecopier.FileEntry
+	56, // 32: filecopier.DirJobs.jobs:type_name -> filecopier.DirJob
+	58, // 33: filecopier.TempFiles.files:type_name -> filecopier.TempFile
+	9,  // 34: filecopier.QueueEntry.req:type_name -> filecopier.CopyRequest
+	13, // 35: filecopier.QueueEntry.resp:type_name -> filecopier.CopyResponse
+	61, // 36: filecopier.QueueEntry.callback:type_name -> filecopier.CallbackDelivery
+	81, // 37: filecopier.CallbackDelivery.request:type_name -> filecopier.CallbackRequest
+	7,  // 38: filecopier.CallbackDelivery.state:type_name -> filecopier.CallbackState
+	61, // 39: filecopier.CallbackOutbox.deliveries:type_name -> filecopier.CallbackDelivery
+	60, // 40: filecopier.CopyEvent.entry:type_name -> filecopier.QueueEntry
+	0,  // 41: filecopier.CopyEvent.status:type_name -> filecopier.CopyStatus
+	60, // 42: filecopier.CopyStatusResponse.entry:type_name -> filecopier.QueueEntry
+	56, // 43: filecopier.CopyStatusResponse.job:type_name -> filecopier.DirJob
+	0,  // 44: filecopier.ListQueueRequest.status:type_name -> filecopier.CopyStatus
+	60, // 45: filecopier.ListQueueResponse.entries:type_name -> filecopier.QueueEntry
+	60, // 46: filecopier.CancelCopyResponse.entry:type_name -> filecopier.QueueEntry
+	60, // 47: filecopier.ListDeadLettersResponse.entries:type_name -> filecopier.QueueEntry
+	13, // 48: filecopier.RequeueDeadLetterResponse.response:type_name -> filecopier.CopyResponse
+	0,  // 49: filecopier.CallbackRequest.status:type_name -> filecopier.CopyStatus
+	9,  // 50: filecopier.FileCopierService.DirCopy:input_type -> filecopier.CopyRequest
+	9,  // 51: filecopier.FileCopierService.QueueCopy:input_type -> filecopier.CopyRequest
+	9,  // 52: filecopier.FileCopierService.Copy:input_type -> filecopier.CopyRequest
+	16, // 53: filecopier.FileCopierService.ReceiveKey:input_type -> filecopier.KeyRequest
+	18, // 54: filecopier.FileCopierService.Accepts:input_type -> filecopier.AcceptsRequest
+	20, // 55: filecopier.FileCopierService.Exists:input_type -> filecopier.ExistsRequest
+	22, // 56: filecopier.FileCopierService.Replicate:input_type -> filecopier.ReplicateRequest
+	30, // 57: filecopier.FileCopierService.PushFile:input_type -> filecopier.FileChunk
+	32, // 58: filecopier.FileCopierService.PullFile:input_type -> filecopier.PullFileRequest
+	34, // 59: filecopier.FileCopierService.GetResumeOffset:input_type -> filecopier.ResumeRequest
+	35, // 60: filecopier.FileCopierService.Checksum:input_type -> filecopier.ChecksumRequest
+	37, // 61: filecopier.FileCopierService.Rename:input_type -> filecopier.RenameRequest
+	39, // 62: filecopier.FileCopierService.Remove:input_type -> filecopier.RemoveRequest
+	42, // 63: filecopier.FileCopierService.ListDir:input_type -> filecopier.ListDirRequest
+	50, // 64: filecopier.FileCopierService.MakeDir:input_type -> filecopier.MakeDirRequest
+	52, // 65: filecopier.FileCopierService.Link:input_type -> filecopier.LinkRequest
+	54, // 66: filecopier.FileCopierService.Symlink:input_type -> filecopier.SymlinkRequest
+	44, // 67: filecopier.FileCopierService.Signature:input_type -> filecopier.SignatureRequest
+	47, // 68: filecopier.FileCopierService.Delta:input_type -> filecopier.DeltaRequest
+	48, // 69: filecopier.FileCopierService.ApplyDelta:input_type -> filecopier.DeltaOp
+	24, // 70: filecopier.FileCopierService.Stat:input_type -> filecopier.StatRequest
+	26, // 71: filecopier.FileCopierService.GetMetadata:input_type -> filecopier.GetMetadataRequest
+	28, // 72: filecopier.FileCopierService.SetMetadata:input_type -> filecopier.SetMetadataRequest
+	63, // 73: filecopier.FileCopierService.GetCopyStatus:input_type -> filecopier.CopyStatusRequest
+	64, // 74: filecopier.FileCopierService.WatchCopy:input_type -> filecopier.WatchCopyRequest
+	67, // 75: filecopier.FileCopierService.ListQueue:input_type -> filecopier.ListQueueRequest
+	69, // 76: filecopier.FileCopierService.CancelCopy:input_type -> filecopier.CancelCopyRequest
+	71, // 77: filecopier.FileCopierService.PauseQueue:input_type -> filecopier.PauseQueueRequest
+	73, // 78: filecopier.FileCopierService.ResumeQueue:input_type -> filecopier.ResumeQueueRequest
+	75, // 79: filecopier.FileCopierService.ListDeadLetters:input_type -> filecopier.ListDeadLettersRequest
+	77, // 80: filecopier.FileCopierService.RequeueDeadLetter:input_type -> filecopier.RequeueDeadLetterRequest
+	79, // 81: filecopier.FileCopierService.PurgeDeadLetters:input_type -> filecopier.PurgeDeadLettersRequest
+	81, // 82: filecopier.FileCopierCallback.Callback:input_type -> filecopier.CallbackRequest
+	13, // 83: filecopier.FileCopierService.DirCopy:output_type -> filecopier.CopyResponse
+	13, // 84: filecopier.FileCopierService.QueueCopy:output_type -> filecopier.CopyResponse
+	13, // 85: filecopier.FileCopierService.Copy:output_type -> filecopier.CopyResponse
+	17, // 86: filecopier.FileCopierService.ReceiveKey:output_type -> filecopier.KeyResponse
+	19, // 87: filecopier.FileCopierService.Accepts:output_type -> filecopier.AcceptsResponse
+	21, // 88: filecopier.FileCopierService.Exists:output_type -> filecopier.ExistsResponse
+	23, // 89: filecopier.FileCopierService.Replicate:output_type -> filecopier.ReplicateResponse
+	31, // 90: filecopier.FileCopierService.PushFile:output_type -> filecopier.PushFileResponse
+	30, // 91: filecopier.FileCopierService.PullFile:output_type -> filecopier.FileChunk
+	33, // 92: filecopier.FileCopierService.GetResumeOffset:output_type -> filecopier.TransferJournal
+	36, // 93: filecopier.FileCopierService.Checksum:output_type -> filecopier.ChecksumResponse
+	38, // 94: filecopier.FileCopierService.Rename:output_type -> filecopier.RenameResponse
+	40, // 95: filecopier.FileCopierService.Remove:output_type -> filecopier.RemoveResponse
+	43, // 96: filecopier.FileCopierService.ListDir:output_type -> filecopier.ListDirResponse
+	51, // 97: filecopier.FileCopierService.MakeDir:output_type -> filecopier.MakeDirResponse
+	53, // 98: filecopier.FileCopierService.Link:output_type -> filecopier.LinkResponse
+	55, // 99: filecopier.FileCopierService.Symlink:output_type -> filecopier.SymlinkResponse
+	46, // 100: filecopier.FileCopierService.Signature:output_type -> filecopier.Signature
+	48, // 101: filecopier.FileCopierService.Delta:output_type -> filecopier.DeltaOp
+	49, // 102: filecopier.FileCopierService.ApplyDelta:output_type -> filecopier.ApplyDeltaResponse
+	25, // 103: filecopier.FileCopierService.Stat:output_type -> filecopier.StatResponse
+	27, // 104: filecopier.FileCopierService.GetMetadata:output_type -> filecopier.GetMetadataResponse
+	29, // 105: filecopier.FileCopierService.SetMetadata:output_type -> filecopier.SetMetadataResponse
+	66, // 106: filecopier.FileCopierService.GetCopyStatus:output_type -> filecopier.CopyStatusResponse
+	65, // 107: filecopier.FileCopierService.WatchCopy:output_type -> filecopier.CopyEvent
+	68, // 108: filecopier.FileCopierService.ListQueue:output_type -> filecopier.ListQueueResponse
+	70, // 109: filecopier.FileCopierService.CancelCopy:output_type -> filecopier.CancelCopyResponse
+	72, // 110: filecopier.FileCopierService.PauseQueue:output_type -> filecopier.PauseQueueResponse
+	74, // 111: filecopier.FileCopierService.ResumeQueue:output_type -> filecopier.ResumeQueueResponse
+	76, // 112: filecopier.FileCopierService.ListDeadLetters:output_type -> filecopier.ListDeadLettersResponse
+	78, // 113: filecopier.FileCopierService.RequeueDeadLetter:output_type -> filecopier.RequeueDeadLetterResponse
+	80, // 114: filecopier.FileCopierService.PurgeDeadLetters:output_type -> filecopier.PurgeDeadLettersResponse
+	82, // 115: filecopier.FileCopierCallback.Callback:output_type -> filecopier.CallbackResponse
+	83, // [83:116] is the sub-list for method output_type
+	50, // [50:83] is the sub-list for method input_type
+	50, // [50:50] is the sub-list for extension type_name
+	50, // [50:50] is the sub-list for extension extendee
+	0,  // [0:50] is the sub-list for field type_name
 }
 
 func init() { file_filecopier_proto_init() }
@@ -940,159 +5880,13 @@ func file_filecopier_proto_init() {
 	if File_filecopier_proto != nil {
 		return
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_filecopier_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CopyRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CopyResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KeyRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*KeyResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AcceptsRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AcceptsResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExistsRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ExistsResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReplicateRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReplicateResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CallbackRequest); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_filecopier_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CallbackResponse); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_filecopier_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   12,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_filecopier_proto_rawDesc), len(file_filecopier_proto_rawDesc)),
+			NumEnums:      9,
+			NumMessages:   75,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -1102,7 +5896,6 @@ func file_filecopier_proto_init() {
 		MessageInfos:      file_filecopier_proto_msgTypes,
 	}.Build()
 	File_filecopier_proto = out.File
-	file_filecopier_proto_rawDesc = nil
 	file_filecopier_proto_goTypes = nil
 	file_filecopier_proto_depIdxs = nil
 }
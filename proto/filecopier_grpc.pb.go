@@ -24,6 +24,31 @@ type FileCopierServiceClient interface {
 	Accepts(ctx context.Context, in *AcceptsRequest, opts ...grpc.CallOption) (*AcceptsResponse, error)
 	Exists(ctx context.Context, in *ExistsRequest, opts ...grpc.CallOption) (*ExistsResponse, error)
 	Replicate(ctx context.Context, in *ReplicateRequest, opts ...grpc.CallOption) (*ReplicateResponse, error)
+	PushFile(ctx context.Context, opts ...grpc.CallOption) (FileCopierService_PushFileClient, error)
+	PullFile(ctx context.Context, in *PullFileRequest, opts ...grpc.CallOption) (FileCopierService_PullFileClient, error)
+	GetResumeOffset(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*TransferJournal, error)
+	Checksum(ctx context.Context, in *ChecksumRequest, opts ...grpc.CallOption) (*ChecksumResponse, error)
+	Rename(ctx context.Context, in *RenameRequest, opts ...grpc.CallOption) (*RenameResponse, error)
+	Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error)
+	ListDir(ctx context.Context, in *ListDirRequest, opts ...grpc.CallOption) (*ListDirResponse, error)
+	MakeDir(ctx context.Context, in *MakeDirRequest, opts ...grpc.CallOption) (*MakeDirResponse, error)
+	Link(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*LinkResponse, error)
+	Symlink(ctx context.Context, in *SymlinkRequest, opts ...grpc.CallOption) (*SymlinkResponse, error)
+	Signature(ctx context.Context, in *SignatureRequest, opts ...grpc.CallOption) (*Signature, error)
+	Delta(ctx context.Context, in *DeltaRequest, opts ...grpc.CallOption) (FileCopierService_DeltaClient, error)
+	ApplyDelta(ctx context.Context, opts ...grpc.CallOption) (FileCopierService_ApplyDeltaClient, error)
+	Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error)
+	GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error)
+	SetMetadata(ctx context.Context, in *SetMetadataRequest, opts ...grpc.CallOption) (*SetMetadataResponse, error)
+	GetCopyStatus(ctx context.Context, in *CopyStatusRequest, opts ...grpc.CallOption) (*CopyStatusResponse, error)
+	WatchCopy(ctx context.Context, in *WatchCopyRequest, opts ...grpc.CallOption) (FileCopierService_WatchCopyClient, error)
+	ListQueue(ctx context.Context, in *ListQueueRequest, opts ...grpc.CallOption) (*ListQueueResponse, error)
+	CancelCopy(ctx context.Context, in *CancelCopyRequest, opts ...grpc.CallOption) (*CancelCopyResponse, error)
+	PauseQueue(ctx context.Context, in *PauseQueueRequest, opts ...grpc.CallOption) (*PauseQueueResponse, error)
+	ResumeQueue(ctx context.Context, in *ResumeQueueRequest, opts ...grpc.CallOption) (*ResumeQueueResponse, error)
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	RequeueDeadLetter(ctx context.Context, in *RequeueDeadLetterRequest, opts ...grpc.CallOption) (*RequeueDeadLetterResponse, error)
+	PurgeDeadLetters(ctx context.Context, in *PurgeDeadLettersRequest, opts ...grpc.CallOption) (*PurgeDeadLettersResponse, error)
 }
 
 type fileCopierServiceClient struct {
@@ -97,6 +122,350 @@ func (c *fileCopierServiceClient) Replicate(ctx context.Context, in *ReplicateRe
 	return out, nil
 }
 
+func (c *fileCopierServiceClient) PushFile(ctx context.Context, opts ...grpc.CallOption) (FileCopierService_PushFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FileCopierService_serviceDesc.Streams[0], "/filecopier.FileCopierService/PushFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileCopierServicePushFileClient{stream}
+	return x, nil
+}
+
+type FileCopierService_PushFileClient interface {
+	Send(*FileChunk) error
+	CloseAndRecv() (*PushFileResponse, error)
+	grpc.ClientStream
+}
+
+type fileCopierServicePushFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileCopierServicePushFileClient) Send(m *FileChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileCopierServicePushFileClient) CloseAndRecv() (*PushFileResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PushFileResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileCopierServiceClient) PullFile(ctx context.Context, in *PullFileRequest, opts ...grpc.CallOption) (FileCopierService_PullFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FileCopierService_serviceDesc.Streams[1], "/filecopier.FileCopierService/PullFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileCopierServicePullFileClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileCopierService_PullFileClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type fileCopierServicePullFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileCopierServicePullFileClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileCopierServiceClient) GetResumeOffset(ctx context.Context, in *ResumeRequest, opts ...grpc.CallOption) (*TransferJournal, error) {
+	out := new(TransferJournal)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/GetResumeOffset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) Checksum(ctx context.Context, in *ChecksumRequest, opts ...grpc.CallOption) (*ChecksumResponse, error) {
+	out := new(ChecksumResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/Checksum", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) Rename(ctx context.Context, in *RenameRequest, opts ...grpc.CallOption) (*RenameResponse, error) {
+	out := new(RenameResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/Rename", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) Remove(ctx context.Context, in *RemoveRequest, opts ...grpc.CallOption) (*RemoveResponse, error) {
+	out := new(RemoveResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/Remove", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) ListDir(ctx context.Context, in *ListDirRequest, opts ...grpc.CallOption) (*ListDirResponse, error) {
+	out := new(ListDirResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/ListDir", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) MakeDir(ctx context.Context, in *MakeDirRequest, opts ...grpc.CallOption) (*MakeDirResponse, error) {
+	out := new(MakeDirResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/MakeDir", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) Link(ctx context.Context, in *LinkRequest, opts ...grpc.CallOption) (*LinkResponse, error) {
+	out := new(LinkResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/Link", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) Symlink(ctx context.Context, in *SymlinkRequest, opts ...grpc.CallOption) (*SymlinkResponse, error) {
+	out := new(SymlinkResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/Symlink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) Signature(ctx context.Context, in *SignatureRequest, opts ...grpc.CallOption) (*Signature, error) {
+	out := new(Signature)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/Signature", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) Delta(ctx context.Context, in *DeltaRequest, opts ...grpc.CallOption) (FileCopierService_DeltaClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FileCopierService_serviceDesc.Streams[2], "/filecopier.FileCopierService/Delta", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileCopierServiceDeltaClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileCopierService_DeltaClient interface {
+	Recv() (*DeltaOp, error)
+	grpc.ClientStream
+}
+
+type fileCopierServiceDeltaClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileCopierServiceDeltaClient) Recv() (*DeltaOp, error) {
+	m := new(DeltaOp)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileCopierServiceClient) ApplyDelta(ctx context.Context, opts ...grpc.CallOption) (FileCopierService_ApplyDeltaClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FileCopierService_serviceDesc.Streams[3], "/filecopier.FileCopierService/ApplyDelta", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileCopierServiceApplyDeltaClient{stream}
+	return x, nil
+}
+
+type FileCopierService_ApplyDeltaClient interface {
+	Send(*DeltaOp) error
+	CloseAndRecv() (*ApplyDeltaResponse, error)
+	grpc.ClientStream
+}
+
+type fileCopierServiceApplyDeltaClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileCopierServiceApplyDeltaClient) Send(m *DeltaOp) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *fileCopierServiceApplyDeltaClient) CloseAndRecv() (*ApplyDeltaResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ApplyDeltaResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileCopierServiceClient) Stat(ctx context.Context, in *StatRequest, opts ...grpc.CallOption) (*StatResponse, error) {
+	out := new(StatResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/Stat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) GetMetadata(ctx context.Context, in *GetMetadataRequest, opts ...grpc.CallOption) (*GetMetadataResponse, error) {
+	out := new(GetMetadataResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/GetMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) SetMetadata(ctx context.Context, in *SetMetadataRequest, opts ...grpc.CallOption) (*SetMetadataResponse, error) {
+	out := new(SetMetadataResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/SetMetadata", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) GetCopyStatus(ctx context.Context, in *CopyStatusRequest, opts ...grpc.CallOption) (*CopyStatusResponse, error) {
+	out := new(CopyStatusResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/GetCopyStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) WatchCopy(ctx context.Context, in *WatchCopyRequest, opts ...grpc.CallOption) (FileCopierService_WatchCopyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_FileCopierService_serviceDesc.Streams[4], "/filecopier.FileCopierService/WatchCopy", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &fileCopierServiceWatchCopyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FileCopierService_WatchCopyClient interface {
+	Recv() (*CopyEvent, error)
+	grpc.ClientStream
+}
+
+type fileCopierServiceWatchCopyClient struct {
+	grpc.ClientStream
+}
+
+func (x *fileCopierServiceWatchCopyClient) Recv() (*CopyEvent, error) {
+	m := new(CopyEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *fileCopierServiceClient) ListQueue(ctx context.Context, in *ListQueueRequest, opts ...grpc.CallOption) (*ListQueueResponse, error) {
+	out := new(ListQueueResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/ListQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) CancelCopy(ctx context.Context, in *CancelCopyRequest, opts ...grpc.CallOption) (*CancelCopyResponse, error) {
+	out := new(CancelCopyResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/CancelCopy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) PauseQueue(ctx context.Context, in *PauseQueueRequest, opts ...grpc.CallOption) (*PauseQueueResponse, error) {
+	out := new(PauseQueueResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/PauseQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) ResumeQueue(ctx context.Context, in *ResumeQueueRequest, opts ...grpc.CallOption) (*ResumeQueueResponse, error) {
+	out := new(ResumeQueueResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/ResumeQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/ListDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) RequeueDeadLetter(ctx context.Context, in *RequeueDeadLetterRequest, opts ...grpc.CallOption) (*RequeueDeadLetterResponse, error) {
+	out := new(RequeueDeadLetterResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/RequeueDeadLetter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fileCopierServiceClient) PurgeDeadLetters(ctx context.Context, in *PurgeDeadLettersRequest, opts ...grpc.CallOption) (*PurgeDeadLettersResponse, error) {
+	out := new(PurgeDeadLettersResponse)
+	err := c.cc.Invoke(ctx, "/filecopier.FileCopierService/PurgeDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // FileCopierServiceServer is the server API for FileCopierService service.
 // All implementations should embed UnimplementedFileCopierServiceServer
 // for forward compatibility
@@ -108,6 +477,31 @@ type FileCopierServiceServer interface {
 	Accepts(context.Context, *AcceptsRequest) (*AcceptsResponse, error)
 	Exists(context.Context, *ExistsRequest) (*ExistsResponse, error)
 	Replicate(context.Context, *ReplicateRequest) (*ReplicateResponse, error)
+	PushFile(FileCopierService_PushFileServer) error
+	PullFile(*PullFileRequest, FileCopierService_PullFileServer) error
+	GetResumeOffset(context.Context, *ResumeRequest) (*TransferJournal, error)
+	Checksum(context.Context, *ChecksumRequest) (*ChecksumResponse, error)
+	Rename(context.Context, *RenameRequest) (*RenameResponse, error)
+	Remove(context.Context, *RemoveRequest) (*RemoveResponse, error)
+	ListDir(context.Context, *ListDirRequest) (*ListDirResponse, error)
+	MakeDir(context.Context, *MakeDirRequest) (*MakeDirResponse, error)
+	Link(context.Context, *LinkRequest) (*LinkResponse, error)
+	Symlink(context.Context, *SymlinkRequest) (*SymlinkResponse, error)
+	Signature(context.Context, *SignatureRequest) (*Signature, error)
+	Delta(*DeltaRequest, FileCopierService_DeltaServer) error
+	ApplyDelta(FileCopierService_ApplyDeltaServer) error
+	Stat(context.Context, *StatRequest) (*StatResponse, error)
+	GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error)
+	SetMetadata(context.Context, *SetMetadataRequest) (*SetMetadataResponse, error)
+	GetCopyStatus(context.Context, *CopyStatusRequest) (*CopyStatusResponse, error)
+	WatchCopy(*WatchCopyRequest, FileCopierService_WatchCopyServer) error
+	ListQueue(context.Context, *ListQueueRequest) (*ListQueueResponse, error)
+	CancelCopy(context.Context, *CancelCopyRequest) (*CancelCopyResponse, error)
+	PauseQueue(context.Context, *PauseQueueRequest) (*PauseQueueResponse, error)
+	ResumeQueue(context.Context, *ResumeQueueRequest) (*ResumeQueueResponse, error)
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	RequeueDeadLetter(context.Context, *RequeueDeadLetterRequest) (*RequeueDeadLetterResponse, error)
+	PurgeDeadLetters(context.Context, *PurgeDeadLettersRequest) (*PurgeDeadLettersResponse, error)
 }
 
 // UnimplementedFileCopierServiceServer should be embedded to have forward compatible implementations.
@@ -135,6 +529,81 @@ func (UnimplementedFileCopierServiceServer) Exists(context.Context, *ExistsReque
 func (UnimplementedFileCopierServiceServer) Replicate(context.Context, *ReplicateRequest) (*ReplicateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Replicate not implemented")
 }
+func (UnimplementedFileCopierServiceServer) PushFile(FileCopierService_PushFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method PushFile not implemented")
+}
+func (UnimplementedFileCopierServiceServer) PullFile(*PullFileRequest, FileCopierService_PullFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method PullFile not implemented")
+}
+func (UnimplementedFileCopierServiceServer) GetResumeOffset(context.Context, *ResumeRequest) (*TransferJournal, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetResumeOffset not implemented")
+}
+func (UnimplementedFileCopierServiceServer) Checksum(context.Context, *ChecksumRequest) (*ChecksumResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checksum not implemented")
+}
+func (UnimplementedFileCopierServiceServer) Rename(context.Context, *RenameRequest) (*RenameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rename not implemented")
+}
+func (UnimplementedFileCopierServiceServer) Remove(context.Context, *RemoveRequest) (*RemoveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Remove not implemented")
+}
+func (UnimplementedFileCopierServiceServer) ListDir(context.Context, *ListDirRequest) (*ListDirResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDir not implemented")
+}
+func (UnimplementedFileCopierServiceServer) MakeDir(context.Context, *MakeDirRequest) (*MakeDirResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MakeDir not implemented")
+}
+func (UnimplementedFileCopierServiceServer) Link(context.Context, *LinkRequest) (*LinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Link not implemented")
+}
+func (UnimplementedFileCopierServiceServer) Symlink(context.Context, *SymlinkRequest) (*SymlinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Symlink not implemented")
+}
+func (UnimplementedFileCopierServiceServer) Signature(context.Context, *SignatureRequest) (*Signature, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Signature not implemented")
+}
+func (UnimplementedFileCopierServiceServer) Delta(*DeltaRequest, FileCopierService_DeltaServer) error {
+	return status.Errorf(codes.Unimplemented, "method Delta not implemented")
+}
+func (UnimplementedFileCopierServiceServer) ApplyDelta(FileCopierService_ApplyDeltaServer) error {
+	return status.Errorf(codes.Unimplemented, "method ApplyDelta not implemented")
+}
+func (UnimplementedFileCopierServiceServer) Stat(context.Context, *StatRequest) (*StatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stat not implemented")
+}
+func (UnimplementedFileCopierServiceServer) GetMetadata(context.Context, *GetMetadataRequest) (*GetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMetadata not implemented")
+}
+func (UnimplementedFileCopierServiceServer) SetMetadata(context.Context, *SetMetadataRequest) (*SetMetadataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMetadata not implemented")
+}
+func (UnimplementedFileCopierServiceServer) GetCopyStatus(context.Context, *CopyStatusRequest) (*CopyStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCopyStatus not implemented")
+}
+func (UnimplementedFileCopierServiceServer) WatchCopy(*WatchCopyRequest, FileCopierService_WatchCopyServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchCopy not implemented")
+}
+func (UnimplementedFileCopierServiceServer) ListQueue(context.Context, *ListQueueRequest) (*ListQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQueue not implemented")
+}
+func (UnimplementedFileCopierServiceServer) CancelCopy(context.Context, *CancelCopyRequest) (*CancelCopyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelCopy not implemented")
+}
+func (UnimplementedFileCopierServiceServer) PauseQueue(context.Context, *PauseQueueRequest) (*PauseQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseQueue not implemented")
+}
+func (UnimplementedFileCopierServiceServer) ResumeQueue(context.Context, *ResumeQueueRequest) (*ResumeQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeQueue not implemented")
+}
+func (UnimplementedFileCopierServiceServer) ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedFileCopierServiceServer) RequeueDeadLetter(context.Context, *RequeueDeadLetterRequest) (*RequeueDeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequeueDeadLetter not implemented")
+}
+func (UnimplementedFileCopierServiceServer) PurgeDeadLetters(context.Context, *PurgeDeadLettersRequest) (*PurgeDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDeadLetters not implemented")
+}
 
 // UnsafeFileCopierServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to FileCopierServiceServer will
@@ -273,40 +742,621 @@ func _FileCopierService_Replicate_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
-var _FileCopierService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "filecopier.FileCopierService",
-	HandlerType: (*FileCopierServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "DirCopy",
-			Handler:    _FileCopierService_DirCopy_Handler,
-		},
-		{
-			MethodName: "QueueCopy",
-			Handler:    _FileCopierService_QueueCopy_Handler,
-		},
-		{
-			MethodName: "Copy",
-			Handler:    _FileCopierService_Copy_Handler,
-		},
-		{
-			MethodName: "ReceiveKey",
-			Handler:    _FileCopierService_ReceiveKey_Handler,
-		},
-		{
-			MethodName: "Accepts",
-			Handler:    _FileCopierService_Accepts_Handler,
-		},
-		{
-			MethodName: "Exists",
+func _FileCopierService_PushFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileCopierServiceServer).PushFile(&fileCopierServicePushFileServer{stream})
+}
+
+type FileCopierService_PushFileServer interface {
+	SendAndClose(*PushFileResponse) error
+	Recv() (*FileChunk, error)
+	grpc.ServerStream
+}
+
+type fileCopierServicePushFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileCopierServicePushFileServer) SendAndClose(m *PushFileResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileCopierServicePushFileServer) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileCopierService_PullFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PullFileRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileCopierServiceServer).PullFile(m, &fileCopierServicePullFileServer{stream})
+}
+
+type FileCopierService_PullFileServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type fileCopierServicePullFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileCopierServicePullFileServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileCopierService_GetResumeOffset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).GetResumeOffset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/GetResumeOffset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).GetResumeOffset(ctx, req.(*ResumeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_Checksum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChecksumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).Checksum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/Checksum",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).Checksum(ctx, req.(*ChecksumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_Rename_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).Rename(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/Rename",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).Rename(ctx, req.(*RenameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_Remove_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).Remove(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/Remove",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).Remove(ctx, req.(*RemoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_ListDir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDirRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).ListDir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/ListDir",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).ListDir(ctx, req.(*ListDirRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_MakeDir_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MakeDirRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).MakeDir(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/MakeDir",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).MakeDir(ctx, req.(*MakeDirRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_Link_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).Link(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/Link",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).Link(ctx, req.(*LinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_Symlink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SymlinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).Symlink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/Symlink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).Symlink(ctx, req.(*SymlinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_Signature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).Signature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/Signature",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).Signature(ctx, req.(*SignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_Delta_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DeltaRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileCopierServiceServer).Delta(m, &fileCopierServiceDeltaServer{stream})
+}
+
+type FileCopierService_DeltaServer interface {
+	Send(*DeltaOp) error
+	grpc.ServerStream
+}
+
+type fileCopierServiceDeltaServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileCopierServiceDeltaServer) Send(m *DeltaOp) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileCopierService_ApplyDelta_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FileCopierServiceServer).ApplyDelta(&fileCopierServiceApplyDeltaServer{stream})
+}
+
+type FileCopierService_ApplyDeltaServer interface {
+	SendAndClose(*ApplyDeltaResponse) error
+	Recv() (*DeltaOp, error)
+	grpc.ServerStream
+}
+
+type fileCopierServiceApplyDeltaServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileCopierServiceApplyDeltaServer) SendAndClose(m *ApplyDeltaResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *fileCopierServiceApplyDeltaServer) Recv() (*DeltaOp, error) {
+	m := new(DeltaOp)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FileCopierService_Stat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).Stat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/Stat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).Stat(ctx, req.(*StatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_GetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).GetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/GetMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).GetMetadata(ctx, req.(*GetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_SetMetadata_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMetadataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).SetMetadata(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/SetMetadata",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).SetMetadata(ctx, req.(*SetMetadataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_GetCopyStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).GetCopyStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/GetCopyStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).GetCopyStatus(ctx, req.(*CopyStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_WatchCopy_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCopyRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FileCopierServiceServer).WatchCopy(m, &fileCopierServiceWatchCopyServer{stream})
+}
+
+type FileCopierService_WatchCopyServer interface {
+	Send(*CopyEvent) error
+	grpc.ServerStream
+}
+
+type fileCopierServiceWatchCopyServer struct {
+	grpc.ServerStream
+}
+
+func (x *fileCopierServiceWatchCopyServer) Send(m *CopyEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FileCopierService_ListQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).ListQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/ListQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).ListQueue(ctx, req.(*ListQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_CancelCopy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelCopyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).CancelCopy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/CancelCopy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).CancelCopy(ctx, req.(*CancelCopyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_PauseQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).PauseQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/PauseQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).PauseQueue(ctx, req.(*PauseQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_ResumeQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).ResumeQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/ResumeQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).ResumeQueue(ctx, req.(*ResumeQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/ListDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_RequeueDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueDeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).RequeueDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/RequeueDeadLetter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).RequeueDeadLetter(ctx, req.(*RequeueDeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FileCopierService_PurgeDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FileCopierServiceServer).PurgeDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/filecopier.FileCopierService/PurgeDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FileCopierServiceServer).PurgeDeadLetters(ctx, req.(*PurgeDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _FileCopierService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "filecopier.FileCopierService",
+	HandlerType: (*FileCopierServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DirCopy",
+			Handler:    _FileCopierService_DirCopy_Handler,
+		},
+		{
+			MethodName: "QueueCopy",
+			Handler:    _FileCopierService_QueueCopy_Handler,
+		},
+		{
+			MethodName: "Copy",
+			Handler:    _FileCopierService_Copy_Handler,
+		},
+		{
+			MethodName: "ReceiveKey",
+			Handler:    _FileCopierService_ReceiveKey_Handler,
+		},
+		{
+			MethodName: "Accepts",
+			Handler:    _FileCopierService_Accepts_Handler,
+		},
+		{
+			MethodName: "Exists",
 			Handler:    _FileCopierService_Exists_Handler,
 		},
 		{
 			MethodName: "Replicate",
 			Handler:    _FileCopierService_Replicate_Handler,
 		},
+		{
+			MethodName: "GetResumeOffset",
+			Handler:    _FileCopierService_GetResumeOffset_Handler,
+		},
+		{
+			MethodName: "Checksum",
+			Handler:    _FileCopierService_Checksum_Handler,
+		},
+		{
+			MethodName: "Rename",
+			Handler:    _FileCopierService_Rename_Handler,
+		},
+		{
+			MethodName: "Remove",
+			Handler:    _FileCopierService_Remove_Handler,
+		},
+		{
+			MethodName: "ListDir",
+			Handler:    _FileCopierService_ListDir_Handler,
+		},
+		{
+			MethodName: "MakeDir",
+			Handler:    _FileCopierService_MakeDir_Handler,
+		},
+		{
+			MethodName: "Link",
+			Handler:    _FileCopierService_Link_Handler,
+		},
+		{
+			MethodName: "Symlink",
+			Handler:    _FileCopierService_Symlink_Handler,
+		},
+		{
+			MethodName: "Signature",
+			Handler:    _FileCopierService_Signature_Handler,
+		},
+		{
+			MethodName: "Stat",
+			Handler:    _FileCopierService_Stat_Handler,
+		},
+		{
+			MethodName: "GetMetadata",
+			Handler:    _FileCopierService_GetMetadata_Handler,
+		},
+		{
+			MethodName: "SetMetadata",
+			Handler:    _FileCopierService_SetMetadata_Handler,
+		},
+		{
+			MethodName: "GetCopyStatus",
+			Handler:    _FileCopierService_GetCopyStatus_Handler,
+		},
+		{
+			MethodName: "ListQueue",
+			Handler:    _FileCopierService_ListQueue_Handler,
+		},
+		{
+			MethodName: "CancelCopy",
+			Handler:    _FileCopierService_CancelCopy_Handler,
+		},
+		{
+			MethodName: "PauseQueue",
+			Handler:    _FileCopierService_PauseQueue_Handler,
+		},
+		{
+			MethodName: "ResumeQueue",
+			Handler:    _FileCopierService_ResumeQueue_Handler,
+		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _FileCopierService_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "RequeueDeadLetter",
+			Handler:    _FileCopierService_RequeueDeadLetter_Handler,
+		},
+		{
+			MethodName: "PurgeDeadLetters",
+			Handler:    _FileCopierService_PurgeDeadLetters_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PushFile",
+			Handler:       _FileCopierService_PushFile_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "PullFile",
+			Handler:       _FileCopierService_PullFile_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Delta",
+			Handler:       _FileCopierService_Delta_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ApplyDelta",
+			Handler:       _FileCopierService_ApplyDelta_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchCopy",
+			Handler:       _FileCopierService_WatchCopy_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "filecopier.proto",
 }
 
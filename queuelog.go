@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// queueLog keeps the queue on disk as an append only log of entry states, the
+// last record written for an id is the current state of that entry
+type queueLog struct {
+	file  string
+	mutex sync.Mutex
+	out   *os.File
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return fmt.Sprintf("%x", b)
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(t int64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, t)
+}
+
+func (e *queueEntry) toProto() *pb.QueueEntry {
+	return &pb.QueueEntry{Req: e.req, Resp: e.resp, TimeAdded: unixNano(e.timeAdded), TimeStarted: unixNano(e.timeStarted), TimeFinished: unixNano(e.timeFinished)}
+}
+
+func fromProto(e *pb.QueueEntry) *queueEntry {
+	return &queueEntry{req: e.GetReq(), resp: e.GetResp(), timeAdded: fromUnixNano(e.GetTimeAdded()),
+		timeStarted: fromUnixNano(e.GetTimeStarted()), timeFinished: fromUnixNano(e.GetTimeFinished())}
+}
+
+// load reads back the entries in the log, in the order they were first
+// queued, and compacts it down to just those entries
+func (q *queueLog) load() ([]*pb.QueueEntry, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var order []string
+	latest := make(map[string]*pb.QueueEntry)
+
+	f, err := os.Open(q.file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		r := bufio.NewReader(f)
+		for {
+			entry := &pb.QueueEntry{}
+			err := protodelim.UnmarshalFrom(r, entry)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				// A crash part way through an append leaves a torn record at the end
+				if !errors.Is(err, io.ErrUnexpectedEOF) {
+					f.Close()
+					return nil, fmt.Errorf("unable to read queue log %v: %w", q.file, err)
+				}
+				break
+			}
+
+			id := entry.GetResp().GetId()
+			if _, ok := latest[id]; !ok {
+				order = append(order, id)
+			}
+			latest[id] = entry
+		}
+		f.Close()
+	}
+
+	var entries []*pb.QueueEntry
+	for _, id := range order {
+		if !latest[id].GetRemoved() {
+			entries = append(entries, latest[id])
+		}
+	}
+	return entries, q.compact(entries)
+}
+
+// compact rewrites the log with a single record for each entry, call with the lock held
+func (q *queueLog) compact(entries []*pb.QueueEntry) error {
+	if q.out != nil {
+		q.out.Close()
+		q.out = nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.file), 0700); err != nil {
+		return err
+	}
+	tmp := q.file + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, entry := range entries {
+		if _, err := protodelim.MarshalTo(w, entry); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.file)
+}
+
+// rewrite replaces the log with the given entries
+func (q *queueLog) rewrite(entries []*pb.QueueEntry) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.compact(entries)
+}
+
+func (q *queueLog) append(entry *pb.QueueEntry) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.out == nil {
+		if err := os.MkdirAll(filepath.Dir(q.file), 0700); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(q.file, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return err
+		}
+		q.out = out
+	}
+
+	if _, err := protodelim.MarshalTo(q.out, entry); err != nil {
+		return err
+	}
+	return q.out.Sync()
+}
+
+// persist records the current state of a queue entry and tells anyone watching it, call with the queue lock held
+func (s *Server) persist(ctx context.Context, entry *queueEntry) {
+	s.notify(entry)
+	for _, q := range s.queue {
+		if q == entry {
+			if err := s.queueLog.append(entry.toProto()); err != nil {
+				s.CtxLog(ctx, fmt.Sprintf("Unable to persist %v: %v", entry.resp.GetId(), err))
+			}
+			return
+		}
+	}
+}
+
+// unpersist records that an entry has been dropped from the queue
+func (s *Server) unpersist(ctx context.Context, entry *queueEntry) {
+	if err := s.queueLog.append(&pb.QueueEntry{Resp: &pb.CopyResponse{Id: entry.resp.GetId()}, Removed: true}); err != nil {
+		s.CtxLog(ctx, fmt.Sprintf("Unable to remove %v: %v", entry.resp.GetId(), err))
+	}
+}
+
+// loadQueue replays the queue from disk, anything that was running when we
+// went down goes back in the queue to be run again
+func (s *Server) loadQueue(ctx context.Context) error {
+	entries, err := s.queueLog.load()
+	if err != nil {
+		return err
+	}
+
+	s.queueMutex.Lock()
+	var pending []*queueEntry
+	loaded := 0
+	for _, e := range entries {
+		entry := fromProto(e)
+		// Anything finished before we went down is only there to be reported on for a while
+		if expired(entry) {
+			continue
+		}
+		if entry.resp.GetStatus() == pb.CopyStatus_IN_PROGRESS {
+			entry.resp.Status = pb.CopyStatus_IN_QUEUE
+		}
+		if entry.resp.GetStatus() == pb.CopyStatus_IN_QUEUE {
+			pending = append(pending, entry)
+		}
+		s.queue = append(s.queue, entry)
+		loaded++
+	}
+	s.queueMutex.Unlock()
+
+	for _, entry := range pending {
+		s.scheduler.push(entry, entry.timeAdded)
+	}
+
+	dead, err := s.deadLetterLog.load()
+	if err != nil {
+		return err
+	}
+	s.queueMutex.Lock()
+	for _, e := range dead {
+		s.deadLetters = append(s.deadLetters, fromProto(e))
+	}
+	s.queueMutex.Unlock()
+	s.CtxLog(ctx, fmt.Sprintf("Loaded %v queue entries, %v to run", loaded, len(pending)))
+	return nil
+}
+
+// queuedTemp is true if the temp file belongs to a copy that's waiting to run
+func (s *Server) queuedTemp(server, path string) bool {
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
+	for _, q := range s.queue {
+		if q.resp.GetStatus() == pb.CopyStatus_IN_QUEUE && q.req.GetOutputServer() == server && tempPath(q.req.GetOutputFile()) == path {
+			return true
+		}
+	}
+	return false
+}
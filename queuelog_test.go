@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+)
+
+// restartServer builds a new server which shares the state on disk of the given one
+func restartServer(t *testing.T, s *Server) *Server {
+	ns := InitTestServer()
+	ns.queueLog.file = s.queueLog.file
+	ns.deadLetterLog.file = s.deadLetterLog.file
+	ns.temps.file = s.temps.file
+	ns.outbox.file = s.outbox.file
+	ns.jobs.file = s.jobs.file
+	if err := ns.loadQueue(context.Background()); err != nil {
+		t.Fatalf("Unable to load queue: %v", err)
+	}
+	if err := ns.outbox.load(); err != nil {
+		t.Fatalf("Unable to load outbox: %v", err)
+	}
+	if err := ns.jobs.load(); err != nil {
+		t.Fatalf("Unable to load jobs: %v", err)
+	}
+	return ns
+}
+
+func TestQueueReplay(t *testing.T) {
+	s := InitTestServer()
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1"})
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "two", OutputFile: "out2"})
+
+	s.queueMutex.Lock()
+	s.queue[0].resp.Status = pb.CopyStatus_IN_PROGRESS
+	s.queue[0].resp.Repeats = 3
+	s.persist(context.Background(), s.queue[0])
+	s.queueMutex.Unlock()
+
+	ns := restartServer(t, s)
+	if len(ns.queue) != 2 {
+		t.Fatalf("Queue was not replayed: %v", ns.queue)
+	}
+	if ns.queue[0].req.GetInputFile() != "one" || ns.queue[0].resp.GetStatus() != pb.CopyStatus_IN_QUEUE || ns.queue[0].resp.GetRepeats() != 3 {
+		t.Errorf("In progress copy was not requeued: %v", ns.queue[0].resp)
+	}
+	if ns.queue[0].resp.GetId() != s.queue[0].resp.GetId() {
+		t.Errorf("Id was lost: %v vs %v", ns.queue[0].resp.GetId(), s.queue[0].resp.GetId())
+	}
+
+	if ns.scheduler.len() != 2 {
+		t.Fatalf("Entries were not scheduled: %v", ns.scheduler.len())
+	}
+	for _, expected := range []string{"one", "two"} {
+		if entry := ns.scheduler.pop(nil); entry.req.GetInputFile() != expected {
+			t.Errorf("Wrong entry run: %v", entry.req)
+		}
+	}
+}
+
+func TestQueueReplayDropsReplaced(t *testing.T) {
+	s := InitTestServer()
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1"})
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1", Override: true})
+
+	ns := restartServer(t, s)
+	if len(ns.queue) != 1 || ns.queue[0].resp.GetId() != s.queue[0].resp.GetId() {
+		t.Errorf("Replaced entry came back: %v", ns.queue)
+	}
+}
+
+func TestQueueReplayTornRecord(t *testing.T) {
+	s := InitTestServer()
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1"})
+
+	f, _ := os.OpenFile(s.queueLog.file, os.O_WRONLY|os.O_APPEND, 0600)
+	f.Write([]byte{0x50, 0x01, 0x02})
+	f.Close()
+
+	ns := restartServer(t, s)
+	if len(ns.queue) != 1 {
+		t.Errorf("Queue was not replayed: %v", ns.queue)
+	}
+
+	// The torn record should have been compacted away
+	ns.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "two", OutputFile: "out2"})
+	ns = restartServer(t, ns)
+	if len(ns.queue) != 2 {
+		t.Errorf("Queue was not replayed after repair: %v", ns.queue)
+	}
+}
+
+func TestCleanQueuePersists(t *testing.T) {
+	s := InitTestServer()
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1"})
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "two", OutputFile: "out2"})
+	s.queue[0].resp.Status = pb.CopyStatus_COMPLETE
+	s.queue[0].timeAdded = time.Now().Add(-time.Hour)
+
+	err := s.cleanQueue(context.Background())
+	if err != nil {
+		t.Fatalf("Clean failed: %v", err)
+	}
+
+	ns := restartServer(t, s)
+	if len(ns.queue) != 1 || ns.queue[0].req.GetInputFile() != "two" {
+		t.Errorf("Cleaned entry came back: %v", ns.queue)
+	}
+}
+
+func TestQueueReplayDropsExpired(t *testing.T) {
+	s := InitTestServer()
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1"})
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "two", OutputFile: "out2"})
+
+	s.queueMutex.Lock()
+	s.queue[0].resp.Status = pb.CopyStatus_COMPLETE
+	s.queue[0].timeFinished = time.Now().Add(-time.Hour)
+	s.persist(context.Background(), s.queue[0])
+	s.queue[1].resp.Status = pb.CopyStatus_COMPLETE
+	s.queue[1].timeFinished = time.Now()
+	s.persist(context.Background(), s.queue[1])
+	s.queueMutex.Unlock()
+
+	ns := restartServer(t, s)
+	if len(ns.queue) != 1 || ns.queue[0].req.GetInputFile() != "two" {
+		t.Fatalf("Expired entry came back: %v", ns.queue)
+	}
+
+	// The old result mustn't stand in for a new copy of the same file
+	resp, err := ns.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1"})
+	if err != nil || resp.GetStatus() != pb.CopyStatus_IN_QUEUE || resp.GetId() == s.queue[0].resp.GetId() {
+		t.Errorf("Expired result was returned: %v, %v", resp, err)
+	}
+}
+
+func TestCleanupKeepsQueuedTemp(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	ioutil.WriteFile(tempPath(dir+"/out.txt"), []byte("partial"), 0640)
+	s.temps.add("", tempPath(dir+"/out.txt"))
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+
+	ns := restartServer(t, s)
+	errs := ns.temps.cleanup(context.Background(), ns.fs, ns.queuedTemp)
+	if len(errs) != 0 {
+		t.Fatalf("Cleanup failed: %v", errs)
+	}
+
+	if _, err := os.Stat(tempPath(dir + "/out.txt")); err != nil {
+		t.Errorf("Temp file of a queued copy was removed: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetCopyStatus(t *testing.T) {
+	s := InitTestServer()
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1", Key: 1, Priority: 10})
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "two", OutputFile: "out2", Key: 2})
+
+	resp, err := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Key: 1})
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if resp.GetEntry().GetReq().GetInputFile() != "one" || resp.GetEntry().GetResp().GetIndexInQueue() != 1 || resp.GetEntry().GetTimeAdded() == 0 {
+		t.Errorf("Bad status: %v", resp)
+	}
+
+	resp, err = s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: queued.GetId()})
+	if err != nil || resp.GetEntry().GetReq().GetKey() != 2 {
+		t.Errorf("Bad status by id: %v, %v", resp, err)
+	}
+
+	_, err = s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Key: 3})
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Missing copy was found: %v", err)
+	}
+
+	_, err = s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{})
+	if status.Convert(err).Code() != codes.InvalidArgument {
+		t.Errorf("Empty request did not fail: %v", err)
+	}
+}
+
+func TestListQueue(t *testing.T) {
+	s := InitTestServer()
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1", OutputServer: "dest"})
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "two", OutputFile: "out2"})
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "three", OutputFile: "out3", OutputServer: "dest"})
+	s.queue[2].resp.Status = pb.CopyStatus_COMPLETE
+
+	resp, err := s.ListQueue(context.Background(), &pb.ListQueueRequest{})
+	if err != nil || len(resp.GetEntries()) != 3 {
+		t.Fatalf("Bad list: %v, %v", resp, err)
+	}
+
+	resp, _ = s.ListQueue(context.Background(), &pb.ListQueueRequest{OutputServer: "dest"})
+	if len(resp.GetEntries()) != 2 {
+		t.Errorf("Bad server filter: %v", resp)
+	}
+
+	resp, _ = s.ListQueue(context.Background(), &pb.ListQueueRequest{OutputServer: "dest", Status: []pb.CopyStatus{pb.CopyStatus_IN_QUEUE}})
+	if len(resp.GetEntries()) != 1 || resp.GetEntries()[0].GetReq().GetInputFile() != "one" {
+		t.Errorf("Bad status filter: %v", resp)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultRetryPolicy fills in anything a request leaves unset
+var defaultRetryPolicy = &pb.RetryPolicy{
+	MaxAttempts:      10,
+	InitialBackoffMs: 1000,
+	MaxBackoffMs:     5 * 60 * 1000,
+	Jitter:           0.2,
+	RetryableCodes:   []int32{int32(codes.Unavailable)},
+}
+
+func validatePolicy(policy *pb.RetryPolicy) error {
+	if policy.GetMaxAttempts() < 0 || policy.GetInitialBackoffMs() < 0 || policy.GetMaxBackoffMs() < 0 {
+		return status.Errorf(codes.InvalidArgument, "Retry policy cannot be negative: %v", policy)
+	}
+	if policy.GetJitter() < 0 || policy.GetJitter() > 1 {
+		return status.Errorf(codes.InvalidArgument, "Jitter must be between 0 and 1: %v", policy.GetJitter())
+	}
+	return nil
+}
+
+// retryPolicy is the policy for a request with the defaults filled in
+func retryPolicy(in *pb.CopyRequest) *pb.RetryPolicy {
+	p := in.GetRetryPolicy()
+	policy := &pb.RetryPolicy{
+		MaxAttempts:      p.GetMaxAttempts(),
+		InitialBackoffMs: p.GetInitialBackoffMs(),
+		MaxBackoffMs:     p.GetMaxBackoffMs(),
+		Jitter:           p.GetJitter(),
+		RetryableCodes:   p.GetRetryableCodes(),
+	}
+
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = defaultRetryPolicy.GetMaxAttempts()
+	}
+	if policy.InitialBackoffMs == 0 {
+		policy.InitialBackoffMs = defaultRetryPolicy.GetInitialBackoffMs()
+	}
+	if policy.MaxBackoffMs == 0 {
+		policy.MaxBackoffMs = defaultRetryPolicy.GetMaxBackoffMs()
+	}
+	if policy.MaxBackoffMs < policy.InitialBackoffMs {
+		policy.MaxBackoffMs = policy.InitialBackoffMs
+	}
+	if p == nil {
+		policy.Jitter = defaultRetryPolicy.GetJitter()
+	}
+	if len(policy.RetryableCodes) == 0 {
+		policy.RetryableCodes = defaultRetryPolicy.GetRetryableCodes()
+	}
+	return policy
+}
+
+func retryable(policy *pb.RetryPolicy, err error) bool {
+	code := int32(status.Convert(err).Code())
+	for _, c := range policy.GetRetryableCodes() {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff is how long to wait before the given retry, doubling each time up to the max
+func backoff(policy *pb.RetryPolicy, repeats int32) time.Duration {
+	wait := time.Duration(policy.GetInitialBackoffMs()) * time.Millisecond
+	max := time.Duration(policy.GetMaxBackoffMs()) * time.Millisecond
+	for i := int32(1); i < repeats && wait < max; i++ {
+		wait *= 2
+	}
+	if wait > max {
+		wait = max
+	}
+
+	return time.Duration(float64(wait) * (1 + policy.GetJitter()*(rand.Float64()*2-1)))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryPolicyDefaults(t *testing.T) {
+	policy := retryPolicy(&pb.CopyRequest{})
+	if policy.GetMaxAttempts() != 10 || policy.GetJitter() != 0.2 || !retryable(policy, status.Errorf(codes.Unavailable, "down")) {
+		t.Errorf("Bad default policy: %v", policy)
+	}
+	if retryable(policy, status.Errorf(codes.NotFound, "missing")) {
+		t.Errorf("Not found was retried")
+	}
+
+	policy = retryPolicy(&pb.CopyRequest{RetryPolicy: &pb.RetryPolicy{MaxAttempts: 3, RetryableCodes: []int32{int32(codes.Internal)}}})
+	if policy.GetMaxAttempts() != 3 || policy.GetJitter() != 0 || policy.GetInitialBackoffMs() != 1000 || !retryable(policy, status.Errorf(codes.Internal, "broken")) {
+		t.Errorf("Bad policy: %v", policy)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := &pb.RetryPolicy{InitialBackoffMs: 100, MaxBackoffMs: 1000}
+	for repeats, expected := range map[int32]time.Duration{1: 100, 2: 200, 3: 400, 4: 800, 5: 1000, 50: 1000} {
+		if backoff(policy, repeats) != expected*time.Millisecond {
+			t.Errorf("Bad backoff for %v: %v", repeats, backoff(policy, repeats))
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if b := backoff(policy, 1); b < 50*time.Millisecond || b > 150*time.Millisecond {
+			t.Errorf("Jitter out of range: %v", b)
+		}
+	}
+}
+
+func TestQueueCopyBadPolicy(t *testing.T) {
+	s := InitTestServer()
+	_, err := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", RetryPolicy: &pb.RetryPolicy{Jitter: 2}})
+	if status.Convert(err).Code() != codes.InvalidArgument {
+		t.Errorf("Bad policy was accepted: %v", err)
+	}
+}
+
+func TestSchedulerHoldsRetry(t *testing.T) {
+	s := newScheduler()
+	held := entryWithPriority("held", 0)
+	held.resp.NextAttemptTime = time.Now().Add(time.Millisecond * 100).UnixNano()
+	s.push(held, time.Now())
+	s.push(entryWithPriority("ready", 10), time.Now())
+
+	if entry := s.pop(nil); entry.req.GetInputFile() != "ready" {
+		t.Errorf("Held entry ran early")
+	}
+
+	start := time.Now()
+	if entry := s.pop(nil); entry != held || time.Now().Sub(start) < time.Millisecond*50 {
+		t.Errorf("Held entry was not run on time: %v", time.Now().Sub(start))
+	}
+}
+
+func TestQueueRetriesWithBackoff(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	tr := &testTransport{failWait: true}
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = tr
+	go s.runQueue()
+
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt",
+		RetryPolicy: &pb.RetryPolicy{MaxAttempts: 3, InitialBackoffMs: 10}})
+
+	for i := 0; i < 100; i++ {
+		resp, _ := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: queued.GetId()})
+		if resp.GetEntry().GetResp().GetRepeats() == 3 {
+			if resp.GetEntry().GetResp().GetErrorCode() != int32(codes.Unavailable) {
+				t.Errorf("Error was not recorded: %v", resp)
+			}
+			return
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+	t.Fatalf("Copy was not retried: %v", len(tr.started))
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// priorityAging is how long an entry has to wait to be treated as one
+// priority level more urgent, so low priority copies can't starve
+const priorityAging = time.Minute
+
+type scheduled struct {
+	entry *queueEntry
+	// due orders the heap, every entry ages at the same rate so this never changes
+	due   time.Time
+	seq   int64
+	index int
+
+	// notBefore holds back an entry which is waiting to be retried, it's
+	// parked until then
+	notBefore time.Time
+	parked    bool
+}
+
+type scheduledHeap []*scheduled
+
+func (h scheduledHeap) Len() int { return len(h) }
+
+func (h scheduledHeap) Less(i, j int) bool {
+	if h[i].due.Equal(h[j].due) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].due.Before(h[j].due)
+}
+
+func (h scheduledHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scheduledHeap) Push(x interface{}) {
+	s := x.(*scheduled)
+	s.index = len(*h)
+	*h = append(*h, s)
+}
+
+func (h *scheduledHeap) Pop() interface{} {
+	old := *h
+	s := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return s
+}
+
+// parkedHeap holds the entries backing off before a retry, the soonest due first
+type parkedHeap struct {
+	scheduledHeap
+}
+
+func (h parkedHeap) Less(i, j int) bool {
+	return h.scheduledHeap[i].notBefore.Before(h.scheduledHeap[j].notBefore)
+}
+
+// scheduler hands out queued copies, lowest priority value first
+type scheduler struct {
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	waiting scheduledHeap
+	parked  parkedHeap
+	entries map[*queueEntry]*scheduled
+	seq     int64
+	paused  bool
+}
+
+func newScheduler() *scheduler {
+	s := &scheduler{entries: make(map[*queueEntry]*scheduled)}
+	s.cond = sync.NewCond(&s.mutex)
+	return s
+}
+
+// push queues an entry, treating it as having waited since the given time
+func (s *scheduler) push(entry *queueEntry, since time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.entries[entry]; ok {
+		return
+	}
+
+	s.seq++
+	sc := &scheduled{entry: entry, due: since.Add(time.Duration(entry.req.GetPriority()) * priorityAging), seq: s.seq}
+	if entry.resp.GetNextAttemptTime() > 0 {
+		sc.notBefore = time.Unix(0, entry.resp.GetNextAttemptTime())
+	}
+	if sc.notBefore.After(time.Now()) {
+		sc.parked = true
+		heap.Push(&s.parked, sc)
+	} else {
+		heap.Push(&s.waiting, sc)
+	}
+	s.entries[entry] = sc
+	s.cond.Signal()
+}
+
+// unpark moves the entries whose retry is due back in with the rest
+func (s *scheduler) unpark(now time.Time) {
+	for len(s.parked.scheduledHeap) > 0 && !s.parked.scheduledHeap[0].notBefore.After(now) {
+		sc := heap.Pop(&s.parked).(*scheduled)
+		sc.parked = false
+		heap.Push(&s.waiting, sc)
+	}
+}
+
+// take removes the most urgent entry which fits, those passed over stay where they are
+func (s *scheduler) take(fits func(*queueEntry) bool) *queueEntry {
+	var passed []*scheduled
+	defer func() {
+		for _, sc := range passed {
+			heap.Push(&s.waiting, sc)
+		}
+	}()
+
+	for len(s.waiting) > 0 {
+		sc := heap.Pop(&s.waiting).(*scheduled)
+		if fits == nil || fits(sc.entry) {
+			delete(s.entries, sc.entry)
+			return sc.entry
+		}
+		passed = append(passed, sc)
+	}
+	return nil
+}
+
+// pop blocks until there's an entry which is ready and fits and we're not
+// paused, the most urgent entry which fits is taken, fits can be nil to take anything
+func (s *scheduler) pop(fits func(*queueEntry) bool) *queueEntry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for {
+		s.unpark(time.Now())
+		if !s.paused {
+			if entry := s.take(fits); entry != nil {
+				return entry
+			}
+		}
+
+		// Come back when the next retry is due if nothing else wakes us first
+		var timer *time.Timer
+		if len(s.parked.scheduledHeap) > 0 {
+			timer = time.AfterFunc(time.Until(s.parked.scheduledHeap[0].notBefore), s.wake)
+		}
+		s.cond.Wait()
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// wake has another look for something to run, once things have changed
+func (s *scheduler) wake() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cond.Broadcast()
+}
+
+// remove takes an entry out of the queue, returning false if it wasn't waiting
+func (s *scheduler) remove(entry *queueEntry) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	sc, ok := s.entries[entry]
+	if !ok {
+		return false
+	}
+	if sc.parked {
+		heap.Remove(&s.parked, sc.index)
+	} else {
+		heap.Remove(&s.waiting, sc.index)
+	}
+	delete(s.entries, entry)
+	return true
+}
+
+// pause stops any more entries being handed out, those already running carry on
+func (s *scheduler) pause() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.paused = true
+}
+
+func (s *scheduler) resume() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.paused = false
+	s.cond.Broadcast()
+}
+
+func (s *scheduler) isPaused() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.paused
+}
+
+func (s *scheduler) len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.waiting) + len(s.parked.scheduledHeap)
+}
+
+// position is the number of copies that will run before the entry, or -1 if it's not waiting
+func (s *scheduler) position(entry *queueEntry) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.unpark(time.Now())
+	sc, ok := s.entries[entry]
+	if !ok {
+		return -1
+	}
+
+	// An entry backing off goes behind everything that's ready now, and
+	// anything else that's due to be retried before it
+	if sc.parked {
+		pos := len(s.waiting)
+		for i := range s.parked.scheduledHeap {
+			if s.parked.Less(i, sc.index) {
+				pos++
+			}
+		}
+		return pos
+	}
+
+	pos := 0
+	for i := range s.waiting {
+		if s.waiting.Less(i, sc.index) {
+			pos++
+		}
+	}
+	return pos
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+)
+
+func entryWithPriority(file string, priority int32) *queueEntry {
+	return &queueEntry{req: &pb.CopyRequest{InputFile: file, Priority: priority}, resp: &pb.CopyResponse{}}
+}
+
+func TestSchedulerPriority(t *testing.T) {
+	s := newScheduler()
+	now := time.Now()
+	s.push(entryWithPriority("low", 100), now)
+	s.push(entryWithPriority("high", 1), now)
+	s.push(entryWithPriority("default", 0), now)
+	s.push(entryWithPriority("default2", 0), now)
+
+	for _, expected := range []string{"default", "default2", "high", "low"} {
+		if entry := s.pop(nil); entry.req.GetInputFile() != expected {
+			t.Errorf("Expected %v, got %v", expected, entry.req.GetInputFile())
+		}
+	}
+}
+
+func TestSchedulerAging(t *testing.T) {
+	s := newScheduler()
+	now := time.Now()
+	s.push(entryWithPriority("new", 10), now)
+	s.push(entryWithPriority("old", 100), now.Add(-priorityAging*91))
+
+	if entry := s.pop(nil); entry.req.GetInputFile() != "old" {
+		t.Errorf("Old entry was starved: %v", entry.req.GetInputFile())
+	}
+}
+
+func TestSchedulerRemoveAndPosition(t *testing.T) {
+	s := newScheduler()
+	now := time.Now()
+	first := entryWithPriority("first", 0)
+	second := entryWithPriority("second", 5)
+	third := entryWithPriority("third", 10)
+	s.push(third, now)
+	s.push(first, now)
+	s.push(second, now)
+
+	if s.position(first) != 0 || s.position(second) != 1 || s.position(third) != 2 {
+		t.Errorf("Bad positions: %v, %v, %v", s.position(first), s.position(second), s.position(third))
+	}
+
+	if !s.remove(second) || s.remove(second) {
+		t.Errorf("Bad remove")
+	}
+	if s.position(second) != -1 || s.position(third) != 1 {
+		t.Errorf("Bad positions after remove: %v, %v", s.position(second), s.position(third))
+	}
+	if s.pop(nil) != first || s.pop(nil) != third {
+		t.Errorf("Bad order after remove")
+	}
+}
+
+func TestSchedulerParksRetries(t *testing.T) {
+	s := newScheduler()
+	now := time.Now()
+	retry := entryWithPriority("retry", 0)
+	retry.resp.NextAttemptTime = now.Add(time.Millisecond * 100).UnixNano()
+	later := entryWithPriority("later", 0)
+	later.resp.NextAttemptTime = now.Add(time.Hour).UnixNano()
+	ready := entryWithPriority("ready", 100)
+	s.push(later, now)
+	s.push(retry, now)
+	s.push(ready, now)
+
+	if s.position(ready) != 0 || s.position(retry) != 1 || s.position(later) != 2 {
+		t.Errorf("Bad positions: %v, %v, %v", s.position(ready), s.position(retry), s.position(later))
+	}
+
+	if s.pop(nil) != ready {
+		t.Errorf("Entry backing off was run first")
+	}
+	if s.pop(nil) != retry || time.Now().Before(now.Add(time.Millisecond*100)) {
+		t.Errorf("Retry was not run once it was due")
+	}
+	if s.len() != 1 || !s.remove(later) || s.len() != 0 {
+		t.Errorf("Parked entry was not removed")
+	}
+}
+
+func TestSchedulerPopWaits(t *testing.T) {
+	s := newScheduler()
+	got := make(chan *queueEntry)
+	go func() { got <- s.pop(nil) }()
+
+	select {
+	case <-got:
+		t.Fatalf("Pop returned from an empty queue")
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	entry := entryWithPriority("file", 0)
+	s.push(entry, time.Now())
+	select {
+	case e := <-got:
+		if e != entry {
+			t.Errorf("Wrong entry: %v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Pop did not wake up")
+	}
+}
+
+func TestQueueCopyReportsPosition(t *testing.T) {
+	s := InitTestServer()
+	s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "one", OutputFile: "out1", Priority: 10})
+	resp, err := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "two", OutputFile: "out2", Priority: 5})
+	if err != nil {
+		t.Fatalf("Queue failed: %v", err)
+	}
+
+	if resp.GetPriority() != 5 || resp.GetIndexInQueue() != 0 {
+		t.Errorf("Bad response: %v", resp)
+	}
+
+	resp, _ = s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "three", OutputFile: "out3", Priority: 20})
+	if resp.GetIndexInQueue() != 2 {
+		t.Errorf("Bad position: %v", resp)
+	}
+}
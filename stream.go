@@ -0,0 +1,418 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sync/atomic"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// chunkSize is the amount of file data carried in a single FileChunk
+const chunkSize = 1024 * 1024
+
+// chunkSource produces the chunks of a file, the first of which carries its metadata
+type chunkSource interface {
+	recv() (*pb.FileChunk, error)
+}
+
+// chunkSink consumes the chunks of a file
+type chunkSink interface {
+	send(chunk *pb.FileChunk) error
+	// close completes the file, abort gives up on it
+	close() error
+	abort()
+}
+
+type fileSource struct {
+	file   *os.File
+	info   os.FileInfo
+	offset int64
+	first  bool
+}
+
+// openFileSource opens a file for streaming, resuming from the given point if the
+// file is the same one that the resume point was taken from
+func openFileSource(path string, resume *pb.TransferJournal) (*fileSource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	source := &fileSource{file: f, info: info, first: true}
+	if resume.GetOffset() > 0 && resume.GetOffset() <= info.Size() &&
+		resume.GetSize() == info.Size() && resume.GetModTime() == info.ModTime().Unix() {
+		if _, err := f.Seek(resume.GetOffset(), io.SeekStart); err != nil {
+			f.Close()
+			return nil, err
+		}
+		source.offset = resume.GetOffset()
+	}
+	return source, nil
+}
+
+func (f *fileSource) recv() (*pb.FileChunk, error) {
+	buf := make([]byte, chunkSize)
+	n, err := io.ReadFull(f.file, buf)
+	if err == io.EOF && !f.first {
+		return nil, io.EOF
+	}
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	chunk := &pb.FileChunk{Data: buf[:n], Offset: f.offset}
+	if f.first {
+		chunk.Path = f.file.Name()
+		chunk.Size = f.info.Size()
+		chunk.Mode = uint32(f.info.Mode().Perm())
+		chunk.ModTime = f.info.ModTime().Unix()
+		f.first = false
+	}
+	f.offset += int64(n)
+	return chunk, nil
+}
+
+func (f *fileSource) close() error {
+	return f.file.Close()
+}
+
+type fileSink struct {
+	path    string
+	journal *journal
+	file    *os.File
+	mode    os.FileMode
+	size    int64
+	modTime time.Time
+	offset  int64
+	chunks  int
+	written int64
+}
+
+func (f *fileSink) open(chunk *pb.FileChunk) error {
+	f.mode = os.FileMode(chunk.GetMode())
+	if f.mode == 0 {
+		f.mode = 0644
+	}
+	f.size = chunk.GetSize()
+	f.modTime = time.Unix(chunk.GetModTime(), 0)
+
+	if chunk.GetOffset() == 0 {
+		file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.mode)
+		f.file = file
+		return err
+	}
+
+	// We're resuming, so keep what we have up to the offset and write from there
+	file, err := os.OpenFile(f.path, os.O_WRONLY, f.mode)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err == nil && info.Size() < chunk.GetOffset() {
+		err = status.Errorf(codes.FailedPrecondition, "Cannot resume %v at %v, only have %v bytes", f.path, chunk.GetOffset(), info.Size())
+	}
+	if err == nil {
+		err = file.Truncate(chunk.GetOffset())
+	}
+	if err == nil {
+		_, err = file.Seek(chunk.GetOffset(), io.SeekStart)
+	}
+	if err != nil {
+		file.Close()
+		f.journal.clear(f.path)
+		return err
+	}
+	f.file = file
+	f.offset = chunk.GetOffset()
+	return nil
+}
+
+func (f *fileSink) send(chunk *pb.FileChunk) error {
+	if f.file == nil {
+		if err := f.open(chunk); err != nil {
+			return err
+		}
+	}
+
+	if chunk.GetOffset() != f.offset {
+		return status.Errorf(codes.Aborted, "Chunk for %v at %v, expected %v", f.path, chunk.GetOffset(), f.offset)
+	}
+
+	n, err := f.file.Write(chunk.GetData())
+	f.written += int64(n)
+	f.offset += int64(n)
+	if err != nil {
+		return err
+	}
+
+	f.chunks++
+	if f.chunks%journalEvery == 0 {
+		return f.checkpoint()
+	}
+	return nil
+}
+
+// checkpoint records everything written so far as safely on disk
+func (f *fileSink) checkpoint() error {
+	if err := f.file.Sync(); err != nil {
+		return err
+	}
+	return f.journal.record(&pb.TransferJournal{Path: f.path, Offset: f.offset, Size: f.size, ModTime: f.modTime.Unix()})
+}
+
+func (f *fileSink) abort() {
+	if f.file != nil {
+		f.checkpoint()
+		f.file.Close()
+	}
+}
+
+func (f *fileSink) close() error {
+	if f.file == nil {
+		return status.Errorf(codes.InvalidArgument, "No data was received for %v", f.path)
+	}
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	if err := f.journal.clear(f.path); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.path, f.mode); err != nil {
+		return err
+	}
+	return os.Chtimes(f.path, f.modTime, f.modTime)
+}
+
+type pushSink struct {
+	stream pb.FileCopierService_PushFileClient
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+	path   string
+}
+
+func (p *pushSink) send(chunk *pb.FileChunk) error {
+	c := &pb.FileChunk{Data: chunk.GetData(), Size: chunk.GetSize(), Mode: chunk.GetMode(), ModTime: chunk.GetModTime(), Offset: chunk.GetOffset()}
+	if len(p.path) > 0 {
+		c.Path = p.path
+		p.path = ""
+	}
+
+	err := p.stream.Send(c)
+	if err == io.EOF {
+		// The receiver has bailed, the real error comes back from the close
+		_, err = p.stream.CloseAndRecv()
+	}
+	return err
+}
+
+func (p *pushSink) close() error {
+	defer p.conn.Close()
+	defer p.cancel()
+	_, err := p.stream.CloseAndRecv()
+	return err
+}
+
+func (p *pushSink) abort() {
+	p.cancel()
+	p.conn.Close()
+}
+
+type pullSource struct {
+	stream pb.FileCopierService_PullFileClient
+	conn   *grpc.ClientConn
+}
+
+func (p *pullSource) recv() (*pb.FileChunk, error) {
+	return p.stream.Recv()
+}
+
+func (p *pullSource) close() error {
+	return p.conn.Close()
+}
+
+// grpcTransport streams files between filecopier servers without using ssh,
+// resuming interrupted transfers from the destination's journal
+type grpcTransport struct {
+	dial    func(ctx context.Context, job, server string) (*grpc.ClientConn, error)
+	isLocal func(server string) bool
+	journal *journal
+}
+
+type streamTransfer struct {
+	copied int64
+	total  int64
+	done   chan error
+}
+
+// resumePoint finds out how much of the file the destination already has
+func (g *grpcTransport) resumePoint(ctx context.Context, server, path string) *pb.TransferJournal {
+	if g.isLocal(server) {
+		return g.journal.get(path)
+	}
+
+	conn, err := g.dial(ctx, "filecopier", server)
+	if err != nil {
+		return &pb.TransferJournal{Path: path}
+	}
+	defer conn.Close()
+
+	resume, err := pb.NewFileCopierServiceClient(conn).GetResumeOffset(ctx, &pb.ResumeRequest{Path: path})
+	if err != nil {
+		return &pb.TransferJournal{Path: path}
+	}
+	return resume
+}
+
+// streams is true if files can be streamed to and from the server
+func (g *grpcTransport) streams(ctx context.Context, server string) bool {
+	if g.isLocal(server) {
+		return true
+	}
+
+	conn, err := g.dial(ctx, "filecopier", server)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	_, err = pb.NewFileCopierServiceClient(conn).GetResumeOffset(ctx, &pb.ResumeRequest{})
+	return err == nil
+}
+
+func (g *grpcTransport) openSource(ctx context.Context, server, path string, resume *pb.TransferJournal) (chunkSource, func() error, error) {
+	if g.isLocal(server) {
+		f, err := openFileSource(path, resume)
+		if err != nil {
+			return nil, nil, err
+		}
+		return f, f.close, nil
+	}
+
+	conn, err := g.dial(ctx, "filecopier", server)
+	if err != nil {
+		return nil, nil, err
+	}
+	stream, err := pb.NewFileCopierServiceClient(conn).PullFile(ctx, &pb.PullFileRequest{
+		Path:    path,
+		Offset:  resume.GetOffset(),
+		Size:    resume.GetSize(),
+		ModTime: resume.GetModTime(),
+	})
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	p := &pullSource{stream: stream, conn: conn}
+	return p, p.close, nil
+}
+
+func (g *grpcTransport) openSink(ctx context.Context, server, path string) (chunkSink, error) {
+	if g.isLocal(server) {
+		return &fileSink{path: path, journal: g.journal}, nil
+	}
+
+	conn, err := g.dial(ctx, "filecopier", server)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stream, err := pb.NewFileCopierServiceClient(conn).PushFile(ctx)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
+	}
+	return &pushSink{stream: stream, conn: conn, cancel: cancel, path: path}, nil
+}
+
+func (g *grpcTransport) start(ctx context.Context, in *pb.CopyRequest) (transfer, error) {
+	resume := g.resumePoint(ctx, in.GetOutputServer(), in.GetOutputFile())
+	source, closeSource, err := g.openSource(ctx, in.GetInputServer(), in.GetInputFile(), resume)
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := g.openSink(ctx, in.GetOutputServer(), in.GetOutputFile())
+	if err != nil {
+		closeSource()
+		return nil, err
+	}
+
+	t := &streamTransfer{done: make(chan error, 1)}
+	go func() {
+		defer closeSource()
+		t.done <- t.pump(ctx, source, sink)
+	}()
+	return t, nil
+}
+
+func (g *grpcTransport) classify(err error, output string) codes.Code {
+	return classifyFile(err)
+}
+
+func (g *grpcTransport) sshKeys() bool {
+	return false
+}
+
+func (t *streamTransfer) pump(ctx context.Context, source chunkSource, sink chunkSink) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			sink.abort()
+			return err
+		}
+
+		chunk, err := source.recv()
+		if err == io.EOF {
+			return sink.close()
+		}
+		if err != nil {
+			sink.abort()
+			return err
+		}
+
+		if chunk.GetSize() > 0 {
+			atomic.StoreInt64(&t.total, chunk.GetSize())
+			atomic.StoreInt64(&t.copied, chunk.GetOffset())
+		}
+		if err := sink.send(chunk); err != nil {
+			sink.abort()
+			return err
+		}
+		atomic.AddInt64(&t.copied, int64(len(chunk.GetData())))
+	}
+}
+
+func (t *streamTransfer) progress() (int64, int64) {
+	return atomic.LoadInt64(&t.copied), atomic.LoadInt64(&t.total)
+}
+
+func (t *streamTransfer) wait() (string, error) {
+	return "", <-t.done
+}
+
+// classifyFile converts an error from a file operation into an error code
+func classifyFile(err error) codes.Code {
+	if st, ok := status.FromError(err); ok {
+		return st.Code()
+	}
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return codes.NotFound
+	case errors.Is(err, fs.ErrPermission):
+		return codes.PermissionDenied
+	}
+	return codes.Internal
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// serveTestServer runs the server in process and routes every dial to it
+func serveTestServer(t *testing.T, s *Server) func(ctx context.Context, job, server string) (*grpc.ClientConn, error) {
+	lis := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	pb.RegisterFileCopierServiceServer(gs, s)
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	return func(ctx context.Context, job, server string) (*grpc.ClientConn, error) {
+		return grpc.NewClient("passthrough:///"+server,
+			grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) { return lis.DialContext(ctx) }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+}
+
+func InitStreamTestServer(t *testing.T) (*Server, string) {
+	s := InitTestServer()
+	s.Registry.Identifier = "local"
+	s.transports[pb.TransportType_GRPC_STREAM] = &grpcTransport{dial: serveTestServer(t, s), isLocal: s.isLocal, journal: s.journal}
+
+	dir, _ := ioutil.TempDir("", "filecopier")
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	// Make the file span several chunks
+	data := bytes.Repeat([]byte("abcdefghij"), chunkSize/4)
+	ioutil.WriteFile(dir+"/in.txt", data, 0640)
+
+	return s, dir
+}
+
+func checkCopied(t *testing.T, dir string) {
+	in, _ := ioutil.ReadFile(dir + "/in.txt")
+	out, err := ioutil.ReadFile(dir + "/out.txt")
+	if err != nil {
+		t.Fatalf("Unable to read copied file: %v", err)
+	}
+	if !bytes.Equal(in, out) {
+		t.Errorf("Files differ: %v vs %v", len(in), len(out))
+	}
+
+	info, _ := os.Stat(dir + "/out.txt")
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("Mode was not preserved: %v", info.Mode())
+	}
+}
+
+func TestStreamPush(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	checkCopied(t, dir)
+}
+
+func TestStreamPull(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputServer: "remote", InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	checkCopied(t, dir)
+}
+
+func TestStreamRelay(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputServer: "remote1", InputFile: dir + "/in.txt", OutputServer: "remote2", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if err != nil {
+		t.Fatalf("Relay failed: %v", err)
+	}
+	checkCopied(t, dir)
+}
+
+func TestStreamPullMissing(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputServer: "remote", InputFile: dir + "/madeup.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Missing file was not reported: %v", err)
+	}
+}
+
+func TestStreamPushBadDestination(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/madeup/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Bad destination was not reported: %v", err)
+	}
+}
+
+func TestStreamSkipsKeyCheck(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.checker = &testChecker{failServer: "remote"}
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputServer: "remote", OutputFile: dir + "/out.txt", Transport: pb.TransportType_GRPC_STREAM})
+	if err != nil {
+		t.Errorf("Stream copy needed keys: %v", err)
+	}
+}
+
+func TestStreamChunkOutOfStep(t *testing.T) {
+	dir := t.TempDir()
+	sink := &fileSink{path: dir + "/out.txt", journal: &journal{dir: dir}}
+	if err := sink.send(&pb.FileChunk{Offset: 0, Data: []byte("abc")}); err != nil {
+		t.Fatalf("First chunk failed: %v", err)
+	}
+
+	// The stream going wrong is a failure, not a bad checksum
+	err := sink.send(&pb.FileChunk{Offset: 10, Data: []byte("def")})
+	if status.Convert(err).Code() != codes.Aborted || finalStatus(err, &pb.CopyResponse{}) != pb.CopyStatus_FAILED {
+		t.Errorf("Bad error for a chunk out of step: %v", err)
+	}
+	sink.file.Close()
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// tempPath is where a copy is written before it's moved into place, so that
+// readers of the destination never see a half written file
+func tempPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".filecopier-tmp")
+}
+
+// outputPath is the file a temp file is going to replace
+func outputPath(temp string) string {
+	base := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(temp), "."), ".filecopier-tmp")
+	return filepath.Join(filepath.Dir(temp), base)
+}
+
+// tempFiles tracks the temp files we've started writing, so that any which
+// are left behind by a crash can be removed when we next start up
+type tempFiles struct {
+	file  string
+	mutex sync.Mutex
+	files []*pb.TempFile
+}
+
+func (t *tempFiles) load() {
+	data, err := ioutil.ReadFile(t.file)
+	if err != nil {
+		return
+	}
+
+	list := &pb.TempFiles{}
+	if proto.Unmarshal(data, list) == nil {
+		t.files = list.GetFiles()
+	}
+}
+
+func (t *tempFiles) save() error {
+	data, err := proto.Marshal(&pb.TempFiles{Files: t.files})
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(t.file, data)
+}
+
+func (t *tempFiles) add(server, path string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.load()
+	for _, f := range t.files {
+		if f.GetServer() == server && f.GetPath() == path {
+			return nil
+		}
+	}
+	t.files = append(t.files, &pb.TempFile{Server: server, Path: path})
+	return t.save()
+}
+
+func (t *tempFiles) done(server, path string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.load()
+	var files []*pb.TempFile
+	for _, f := range t.files {
+		if f.GetServer() != server || f.GetPath() != path {
+			files = append(files, f)
+		}
+	}
+	t.files = files
+	return t.save()
+}
+
+// cleanup removes the temp files left by copies which never finished, other
+// than those we want to keep, any we can't reach are kept for next time
+func (t *tempFiles) cleanup(ctx context.Context, fs fileSystem, keep func(server, path string) bool) []error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.load()
+	var files []*pb.TempFile
+	var errs []error
+	for _, f := range t.files {
+		if keep(f.GetServer(), f.GetPath()) {
+			files = append(files, f)
+			continue
+		}
+		if err := fs.remove(ctx, f.GetServer(), f.GetPath()); err != nil {
+			files = append(files, f)
+			errs = append(errs, fmt.Errorf("unable to remove %v from %v: %w", f.GetPath(), f.GetServer(), err))
+		}
+	}
+	t.files = files
+	if err := t.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// renameFile moves a finished copy into place
+func renameFile(from, to string) error {
+	if err := os.Rename(from, to); err != nil {
+		return status.Errorf(classifyFile(err), "Unable to move %v to %v: %v", from, to, err)
+	}
+	return nil
+}
+
+// removeFile deletes a file along with any journal we hold for it, a file
+// which is already gone is not an error
+func removeFile(j *journal, path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return status.Errorf(classifyFile(err), "Unable to remove %v: %v", path, err)
+	}
+	if err := j.clear(path); err != nil {
+		return status.Errorf(codes.Internal, "Unable to clear journal for %v: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTempPath(t *testing.T) {
+	if tempPath("/media/music/song.mp3") != "/media/music/.song.mp3.filecopier-tmp" {
+		t.Errorf("Bad temp path: %v", tempPath("/media/music/song.mp3"))
+	}
+}
+
+func TestCopyLeavesNoTemp(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+	checkCopied(t, dir)
+
+	if _, err := os.Stat(tempPath(dir + "/out.txt")); !os.IsNotExist(err) {
+		t.Errorf("Temp file was left behind: %v", err)
+	}
+	s.temps.load()
+	if len(s.temps.files) != 0 {
+		t.Errorf("Temp file is still tracked: %v", s.temps.files)
+	}
+}
+
+func TestFailedVerifyKeepsOutput(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	ioutil.WriteFile(dir+"/out.txt", []byte("original"), 0640)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &testTransport{}
+	ioutil.WriteFile(tempPath(dir+"/out.txt"), []byte("broken"), 0640)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+	if status.Convert(err).Code() != codes.DataLoss {
+		t.Fatalf("Bad copy was not caught: %v", err)
+	}
+
+	out, _ := ioutil.ReadFile(dir + "/out.txt")
+	if string(out) != "original" {
+		t.Errorf("Output was overwritten by a bad copy: %v", string(out))
+	}
+	if _, err := os.Stat(tempPath(dir + "/out.txt")); !os.IsNotExist(err) {
+		t.Errorf("Bad temp file was left behind: %v", err)
+	}
+}
+
+func TestRetryableFailureKeepsTemp(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &testTransport{failWait: true}
+	ioutil.WriteFile(tempPath(dir+"/out.txt"), []byte("partial"), 0640)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+	if status.Convert(err).Code() != codes.Unavailable {
+		t.Fatalf("Copy did not fail correctly: %v", err)
+	}
+
+	if _, err := os.Stat(tempPath(dir + "/out.txt")); err != nil {
+		t.Errorf("Partial copy was removed: %v", err)
+	}
+}
+
+func TestTempCleanup(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	ioutil.WriteFile(tempPath(dir+"/out.txt"), []byte("partial"), 0640)
+	s.temps.add("", tempPath(dir+"/out.txt"))
+
+	// A fresh server picks up the list from disk
+	ns := InitTestServer()
+	ns.temps.file = s.temps.file
+	errs := ns.temps.cleanup(context.Background(), ns.fs, ns.queuedTemp)
+	if len(errs) != 0 {
+		t.Fatalf("Cleanup failed: %v", errs)
+	}
+
+	if _, err := os.Stat(tempPath(dir + "/out.txt")); !os.IsNotExist(err) {
+		t.Errorf("Orphaned temp file was not removed: %v", err)
+	}
+	ns.temps.load()
+	if len(ns.temps.files) != 0 {
+		t.Errorf("Temp file is still tracked: %v", ns.temps.files)
+	}
+}
+
+func TestRenameRemove(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+
+	_, err := s.Rename(context.Background(), &pb.RenameRequest{From: dir + "/in.txt", To: dir + "/moved.txt"})
+	if err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+
+	_, err = s.Remove(context.Background(), &pb.RemoveRequest{Path: dir + "/moved.txt"})
+	if err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, err := os.Stat(dir + "/moved.txt"); !os.IsNotExist(err) {
+		t.Errorf("File was not removed: %v", err)
+	}
+
+	_, err = s.Remove(context.Background(), &pb.RemoveRequest{Path: dir + "/moved.txt"})
+	if err != nil {
+		t.Errorf("Removing a missing file failed: %v", err)
+	}
+
+	_, err = s.Rename(context.Background(), &pb.RenameRequest{From: dir + "/moved.txt", To: dir + "/other.txt"})
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Bad rename did not fail: %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// transfer is a single copy which has been started by a transport
+type transfer interface {
+	// progress returns the bytes copied so far and the total bytes, zero if unknown
+	progress() (int64, int64)
+	// wait blocks until the copy is done, returning any output it produced
+	wait() (string, error)
+}
+
+// transport is a mechanism for moving a file between two servers
+type transport interface {
+	start(ctx context.Context, in *pb.CopyRequest) (transfer, error)
+	classify(err error, output string) codes.Code
+	// sshKeys is true if the servers need to have exchanged keys before copying
+	sshKeys() bool
+}
+
+func (s *Server) isLocal(server string) bool {
+	return len(server) == 0 || server == s.Registry.GetIdentifier()
+}
+
+// router is a transport which passes each copy on to whichever transport suits it
+type router interface {
+	route(ctx context.Context, in *pb.CopyRequest) transport
+}
+
+// getTransport picks the transport that will run the copy, anything depending
+// on how the copy is done needs to ask the one that comes back
+func (s *Server) getTransport(ctx context.Context, in *pb.CopyRequest) (transport, error) {
+	if in.GetTransferMode() == pb.TransferMode_DELTA_TRANSFER {
+		return s.delta, nil
+	}
+	if t, ok := s.transports[in.GetTransport()]; ok {
+		if r, ok := t.(router); ok {
+			return r.route(ctx, in), nil
+		}
+		return t, nil
+	}
+	return nil, status.Errorf(codes.Unimplemented, "Transport %v is not supported", in.GetTransport())
+}
+
+// commandTransfer is a copy being run by an external command
+type commandTransfer struct {
+	command *exec.Cmd
+	output  *bytes.Buffer
+
+	// The command doesn't tell us how it's getting on, so we follow the size
+	// of the output when it's being written here
+	dest  string
+	total int64
+}
+
+// commandGrace is how long a cancelled command gets to exit before it's killed
+const commandGrace = time.Second * 10
+
+func newCommand(ctx context.Context, command string, args ...string) *commandTransfer {
+	c := &commandTransfer{command: exec.CommandContext(ctx, command, args...), output: &bytes.Buffer{}}
+	c.command.Stderr = c.output
+	c.command.Cancel = func() error { return c.command.Process.Signal(syscall.SIGTERM) }
+	c.command.WaitDelay = commandGrace
+	return c
+}
+
+func startCommand(ctx context.Context, command string, args ...string) (transfer, error) {
+	c := newCommand(ctx, command, args...)
+	return c, c.command.Start()
+}
+
+// follow sets up progress tracking for whichever ends of the copy are on this server
+func (c *commandTransfer) follow(isLocal func(server string) bool, in *pb.CopyRequest) {
+	if isLocal(in.GetInputServer()) {
+		if info, err := os.Stat(in.GetInputFile()); err == nil {
+			c.total = info.Size()
+		}
+	}
+	if isLocal(in.GetOutputServer()) {
+		c.dest = in.GetOutputFile()
+	}
+}
+
+func (c *commandTransfer) progress() (int64, int64) {
+	if len(c.dest) == 0 {
+		return 0, c.total
+	}
+	info, err := os.Stat(c.dest)
+	if err != nil {
+		return 0, c.total
+	}
+	return info.Size(), c.total
+}
+
+func (c *commandTransfer) wait() (string, error) {
+	err := c.command.Wait()
+	return strings.TrimSpace(c.output.String()), err
+}
+
+// classifySSH converts the output of an ssh based copy into an error code
+func classifySSH(output string) codes.Code {
+	switch {
+	case strings.Contains(output, "lost connection"),
+		strings.Contains(output, "Connection refused"),
+		strings.Contains(output, "Connection reset"),
+		strings.Contains(output, "Connection timed out"):
+		return codes.Unavailable
+	case strings.Contains(output, "No such file or directory"):
+		return codes.NotFound
+	case strings.Contains(output, "Permission denied"):
+		return codes.PermissionDenied
+	}
+	return codes.Internal
+}
+
+type scpTransport struct {
+	command    string
+	copyString func(server, file string) string
+	isLocal    func(server string) bool
+
+	// local runs copies with both ends on this server, rather than forking scp
+	local transport
+	// stream runs copies with peers that can stream files, which unlike scp
+	// can pick up an interrupted copy where it left off
+	stream *grpcTransport
+}
+
+// route keeps scp, and the keys it needs, for servers that can't copy any other way
+func (t *scpTransport) route(ctx context.Context, in *pb.CopyRequest) transport {
+	if t.local != nil && t.isLocal(in.GetInputServer()) && t.isLocal(in.GetOutputServer()) {
+		return t.local
+	}
+	if t.stream != nil && t.stream.streams(ctx, in.GetInputServer()) && t.stream.streams(ctx, in.GetOutputServer()) {
+		return t.stream
+	}
+	return t
+}
+
+func (t *scpTransport) start(ctx context.Context, in *pb.CopyRequest) (transfer, error) {
+	c := newCommand(ctx, t.command, "-p", "-o", "StrictHostKeyChecking=no",
+		t.copyString(in.GetInputServer(), in.GetInputFile()), t.copyString(in.GetOutputServer(), in.GetOutputFile()))
+	c.follow(t.isLocal, in)
+	return c, c.command.Start()
+}
+
+func (t *scpTransport) classify(err error, output string) codes.Code {
+	// A copy that never ran scp has no output to go on
+	var exitErr *exec.ExitError
+	if len(output) == 0 && !errors.As(err, &exitErr) {
+		return classifyFile(err)
+	}
+	return classifySSH(output)
+}
+
+func (t *scpTransport) sshKeys() bool {
+	return true
+}
+
+type rsyncTransport struct {
+	command    string
+	copyString func(server, file string) string
+	isLocal    func(server string) bool
+}
+
+func (t *rsyncTransport) start(ctx context.Context, in *pb.CopyRequest) (transfer, error) {
+	// We're already writing to a temp file, so rsync can write straight into it
+	// rather than its own, which also lets us follow its progress. A retried copy
+	// finds what the last attempt left there and carries on from the end of it.
+	c := newCommand(ctx, t.command, "-pt", "--inplace", "--partial", "--append-verify", "-e", "ssh -o StrictHostKeyChecking=no",
+		t.copyString(in.GetInputServer(), in.GetInputFile()), t.copyString(in.GetOutputServer(), in.GetOutputFile()))
+	c.follow(t.isLocal, in)
+	return c, c.command.Start()
+}
+
+func (t *rsyncTransport) classify(err error, output string) codes.Code {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		switch exitErr.ExitCode() {
+		// Socket, protocol stream and timeout failures can all be retried
+		case 10, 12, 30, 35:
+			return codes.Unavailable
+		}
+	}
+	return classifySSH(output)
+}
+
+func (t *rsyncTransport) sshKeys() bool {
+	return true
+}
+
+// localTransport copies files on this server without shelling out
+type localTransport struct {
+	isLocal func(server string) bool
+}
+
+type localTransfer struct {
+	copied int64
+	total  int64
+	done   chan error
+}
+
+func (t *localTransport) start(ctx context.Context, in *pb.CopyRequest) (transfer, error) {
+	if !t.isLocal(in.GetInputServer()) || !t.isLocal(in.GetOutputServer()) {
+		return nil, status.Errorf(codes.FailedPrecondition, "Local copy cannot run between %v and %v", in.GetInputServer(), in.GetOutputServer())
+	}
+
+	src, err := os.Open(in.GetInputFile())
+	if err != nil {
+		return nil, err
+	}
+	info, err := src.Stat()
+	if err != nil {
+		src.Close()
+		return nil, err
+	}
+
+	l := &localTransfer{total: info.Size(), done: make(chan error, 1)}
+	go func() {
+		defer src.Close()
+		l.done <- copyLocal(ctx, src, info, in.GetOutputFile(), &l.copied)
+	}()
+	return l, nil
+}
+
+func (t *localTransport) classify(err error, output string) codes.Code {
+	return classifyFile(err)
+}
+
+func (t *localTransport) sshKeys() bool {
+	return false
+}
+
+func (l *localTransfer) progress() (int64, int64) {
+	return atomic.LoadInt64(&l.copied), l.total
+}
+
+func (l *localTransfer) wait() (string, error) {
+	return "", <-l.done
+}
+
+// contextReader stops reading once the context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+type countingWriter struct {
+	w     io.Writer
+	count *int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	atomic.AddInt64(c.count, int64(n))
+	return n, err
+}
+
+// copyLocal copies src to dst, preserving mode and times in the same way as scp -p
+func copyLocal(ctx context.Context, src *os.File, info os.FileInfo, dst string, copied *int64) error {
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	err = copyContents(ctx, out, src, info.Size(), copied)
+	if err != nil {
+		out.Close()
+		return fmt.Errorf("unable to copy %v to %v: %w", src.Name(), dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testTransfer struct {
+	output string
+	err    error
+}
+
+func (t *testTransfer) progress() (int64, int64) {
+	return 0, 0
+}
+
+func (t *testTransfer) wait() (string, error) {
+	return t.output, t.err
+}
+
+type testTransport struct {
+	failStart bool
+	failWait  bool
+	started   []*pb.CopyRequest
+}
+
+func (t *testTransport) start(ctx context.Context, in *pb.CopyRequest) (transfer, error) {
+	if t.failStart {
+		return nil, fmt.Errorf("Built to fail")
+	}
+	t.started = append(t.started, in)
+	if t.failWait {
+		return &testTransfer{output: "Connection reset by peer", err: fmt.Errorf("Built to fail")}, nil
+	}
+	return &testTransfer{}, nil
+}
+
+func (t *testTransport) classify(err error, output string) codes.Code {
+	return classifySSH(output)
+}
+
+func (t *testTransport) sshKeys() bool {
+	return true
+}
+
+func TestRunCopyWithTransport(t *testing.T) {
+	s := InitTestServer()
+	tr := &testTransport{}
+	s.transports[pb.TransportType_RSYNC] = tr
+
+	// The test transport doesn't copy anything, so leave a file for it to move into place
+	dir := t.TempDir()
+	ioutil.WriteFile(tempPath(dir+"/out"), []byte("copied"), 0644)
+
+	err := s.runCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: dir + "/out", Transport: pb.TransportType_RSYNC, SkipVerify: true}, &pb.CopyResponse{})
+	if err != nil {
+		t.Fatalf("Copy failed: %v", err)
+	}
+
+	if len(tr.started) != 1 || tr.started[0].GetInputFile() != "in" || tr.started[0].GetOutputFile() != tempPath(dir+"/out") {
+		t.Errorf("Transport was not used: %v", tr.started)
+	}
+}
+
+func TestRunCopyTransportFailStart(t *testing.T) {
+	s := InitTestServer()
+	s.transports[pb.TransportType_RSYNC] = &testTransport{failStart: true}
+
+	err := s.runCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", Transport: pb.TransportType_RSYNC}, &pb.CopyResponse{})
+	if status.Convert(err).Code() != codes.Internal {
+		t.Errorf("Bad start was not internal: %v", err)
+	}
+}
+
+func TestRunCopyTransportFailWait(t *testing.T) {
+	s := InitTestServer()
+	s.transports[pb.TransportType_RSYNC] = &testTransport{failWait: true}
+
+	err := s.runCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", Transport: pb.TransportType_RSYNC}, &pb.CopyResponse{})
+	if status.Convert(err).Code() != codes.Unavailable {
+		t.Errorf("Lost connection was not classified as unavailable: %v", err)
+	}
+}
+
+func TestRunCopyMissingTransport(t *testing.T) {
+	s := InitTestServer()
+	delete(s.transports, pb.TransportType_RSYNC)
+
+	err := s.runCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", Transport: pb.TransportType_RSYNC}, &pb.CopyResponse{})
+	if status.Convert(err).Code() != codes.Unimplemented {
+		t.Errorf("Missing transport did not fail correctly: %v", err)
+	}
+}
+
+func TestLocalTransport(t *testing.T) {
+	s := InitTestServer()
+	dir, _ := ioutil.TempDir("", "filecopier")
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(dir+"/in.txt", []byte("testing"), 0600)
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL})
+	if err != nil {
+		t.Fatalf("Local copy failed: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(dir + "/out.txt")
+	if err != nil || string(data) != "testing" {
+		t.Errorf("Bad copy: %v, %v", string(data), err)
+	}
+	info, _ := os.Stat(dir + "/out.txt")
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Mode was not preserved: %v", info.Mode())
+	}
+}
+
+func TestLocalTransportRemoteServer(t *testing.T) {
+	s := InitTestServer()
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: "in.txt", InputServer: "remote", OutputFile: "out.txt", Transport: pb.TransportType_LOCAL})
+	if status.Convert(err).Code() != codes.FailedPrecondition {
+		t.Errorf("Remote local copy did not fail: %v", err)
+	}
+}
+
+func TestLocalTransportMissingFile(t *testing.T) {
+	s := InitTestServer()
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: "madeup/in.txt", OutputFile: "out.txt", Transport: pb.TransportType_LOCAL})
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Missing file did not fail correctly: %v", err)
+	}
+}
+
+func TestClassifySSH(t *testing.T) {
+	tests := map[string]codes.Code{
+		"lost connection":                               codes.Unavailable,
+		"scp: madeup: No such file or directory":        codes.NotFound,
+		"scp: /root/blah: Permission denied":            codes.PermissionDenied,
+		"something else entirely has gone wrong":        codes.Internal,
+		"ssh: connect to host blah: Connection refused": codes.Unavailable,
+	}
+
+	for output, code := range tests {
+		if classifySSH(output) != code {
+			t.Errorf("Bad classification of %v: %v", output, classifySSH(output))
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchInterval is how often watchers are told how a running copy is getting on
+var watchInterval = time.Second * 5
+
+// finished is true once a copy isn't going to change state again
+func finished(st pb.CopyStatus) bool {
+	switch st {
+	case pb.CopyStatus_COMPLETE, pb.CopyStatus_VERIFY_FAILED, pb.CopyStatus_CANCELLED, pb.CopyStatus_DEAD_LETTERED, pb.CopyStatus_FAILED, pb.CopyStatus_SKIPPED:
+		return true
+	}
+	return false
+}
+
+// copyEvent describes an entry as it stands, call with the queue lock held
+func (s *Server) copyEvent(entry *queueEntry) *pb.CopyEvent {
+	p := s.snapshot(entry)
+	return &pb.CopyEvent{
+		Entry:          p,
+		Status:         p.GetResp().GetStatus(),
+		BytesDone:      p.GetResp().GetBytesDone(),
+		BytesTotal:     p.GetResp().GetBytesTotal(),
+		BytesPerSecond: p.GetResp().GetBytesPerSecond(),
+		EtaMillis:      p.GetResp().GetEtaMillis(),
+	}
+}
+
+// notify tells anyone watching the entry that it's changed, call with the queue lock held
+func (s *Server) notify(entry *queueEntry) {
+	if len(entry.watchers) == 0 {
+		return
+	}
+
+	e := s.copyEvent(entry)
+	for _, w := range entry.watchers {
+		// Watchers which fall behind pick up the latest state on their next tick
+		select {
+		case w <- e:
+		default:
+		}
+	}
+}
+
+func (s *Server) unwatch(entry *queueEntry, events chan *pb.CopyEvent) {
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+
+	var watchers []chan *pb.CopyEvent
+	for _, w := range entry.watchers {
+		if w != events {
+			watchers = append(watchers, w)
+		}
+	}
+	entry.watchers = watchers
+}
+
+// WatchCopy streams the changes to a copy, along with its progress while it runs, until it finishes
+func (s *Server) WatchCopy(req *pb.WatchCopyRequest, stream pb.FileCopierService_WatchCopyServer) error {
+	if len(req.GetId()) == 0 && req.GetKey() == 0 {
+		return status.Errorf(codes.InvalidArgument, "You need to supply an id or a key")
+	}
+
+	s.queueMutex.Lock()
+	entry := s.findEntry(req.GetId(), req.GetKey())
+	if entry == nil {
+		s.queueMutex.Unlock()
+		return status.Errorf(codes.NotFound, "No copy found for %v", req)
+	}
+	events := make(chan *pb.CopyEvent, 10)
+	entry.watchers = append(entry.watchers, events)
+	last := s.copyEvent(entry)
+	s.queueMutex.Unlock()
+	defer s.unwatch(entry, events)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		if err := stream.Send(last); err != nil {
+			return err
+		}
+		if finished(last.GetStatus()) {
+			return nil
+		}
+
+		next := last
+		for next == last {
+			select {
+			case <-stream.Context().Done():
+				return status.FromContextError(stream.Context().Err()).Err()
+			case next = <-events:
+			case <-ticker.C:
+				s.queueMutex.Lock()
+				e := s.copyEvent(entry)
+				s.queueMutex.Unlock()
+
+				// Only running copies have anything new to say on a tick
+				if e.GetStatus() != last.GetStatus() || e.GetStatus() == pb.CopyStatus_IN_PROGRESS {
+					next = e
+				}
+			}
+		}
+		last = next
+	}
+}
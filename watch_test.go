@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testWatchStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events []*pb.CopyEvent
+}
+
+func (t *testWatchStream) Context() context.Context {
+	return t.ctx
+}
+
+func (t *testWatchStream) Send(e *pb.CopyEvent) error {
+	t.events = append(t.events, e)
+	return nil
+}
+
+type fixedTransfer struct {
+	done, total int64
+}
+
+func (f *fixedTransfer) progress() (int64, int64) {
+	return f.done, f.total
+}
+
+func (f *fixedTransfer) wait() (string, error) {
+	return "", nil
+}
+
+// waitForWatcher blocks until someone is watching the copy
+func waitForWatcher(t *testing.T, s *Server, id string) {
+	for i := 0; i < 100; i++ {
+		s.queueMutex.Lock()
+		watched := len(s.findEntry(id, 0).watchers) > 0
+		s.queueMutex.Unlock()
+		if watched {
+			return
+		}
+		time.Sleep(time.Millisecond * 20)
+	}
+	t.Fatalf("Nobody is watching %v", id)
+}
+
+func TestWatchCopy(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Transport: pb.TransportType_LOCAL, Key: 20})
+
+	stream := &testWatchStream{ctx: context.Background()}
+	done := make(chan error)
+	go func() {
+		done <- s.WatchCopy(&pb.WatchCopyRequest{Key: 20}, stream)
+	}()
+	waitForWatcher(t, s, queued.GetId())
+	go s.runQueue()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+
+	var statuses []pb.CopyStatus
+	for _, e := range stream.events {
+		if len(statuses) == 0 || statuses[len(statuses)-1] != e.GetStatus() {
+			statuses = append(statuses, e.GetStatus())
+		}
+	}
+	if len(statuses) != 3 || statuses[0] != pb.CopyStatus_IN_QUEUE || statuses[1] != pb.CopyStatus_IN_PROGRESS || statuses[2] != pb.CopyStatus_COMPLETE {
+		t.Errorf("Bad transitions: %v", statuses)
+	}
+	last := stream.events[len(stream.events)-1]
+	if last.GetEntry().GetResp().GetId() != queued.GetId() || last.GetEntry().GetResp().GetBytesTransferred() != chunkSize*5/2 {
+		t.Errorf("Bad final event: %v", last)
+	}
+}
+
+func TestWatchFinishedCopy(t *testing.T) {
+	s := InitTestServer()
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: queued.GetId()})
+
+	stream := &testWatchStream{ctx: context.Background()}
+	if err := s.WatchCopy(&pb.WatchCopyRequest{Id: queued.GetId()}, stream); err != nil {
+		t.Fatalf("Watch failed: %v", err)
+	}
+	if len(stream.events) != 1 || stream.events[0].GetStatus() != pb.CopyStatus_CANCELLED {
+		t.Errorf("Bad events: %v", stream.events)
+	}
+
+	err := s.WatchCopy(&pb.WatchCopyRequest{Id: "madeup"}, stream)
+	if status.Convert(err).Code() != codes.NotFound {
+		t.Errorf("Watched a missing copy: %v", err)
+	}
+}
+
+func TestWatchReplacedCopy(t *testing.T) {
+	s := InitTestServer()
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", Callback: "caller"})
+
+	stream := &testWatchStream{ctx: context.Background()}
+	done := make(chan error)
+	go func() {
+		done <- s.WatchCopy(&pb.WatchCopyRequest{Id: queued.GetId()}, stream)
+	}()
+	waitForWatcher(t, s, queued.GetId())
+
+	replacement, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out", Override: true})
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Watch failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Watch of a replaced copy did not finish")
+	}
+
+	last := stream.events[len(stream.events)-1]
+	if last.GetStatus() != pb.CopyStatus_CANCELLED || last.GetEntry().GetResp().GetError() != "Replaced by "+replacement.GetId() {
+		t.Errorf("Bad final event: %v", last)
+	}
+	if cb := waitForCallback(t, s); cb.GetId() != queued.GetId() || cb.GetStatus() != pb.CopyStatus_CANCELLED {
+		t.Errorf("Bad callback: %v", cb)
+	}
+}
+
+func TestWatchCopyGivesUp(t *testing.T) {
+	s := InitTestServer()
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	err := s.WatchCopy(&pb.WatchCopyRequest{Id: queued.GetId()}, &testWatchStream{ctx: ctx})
+	if status.Convert(err).Code() != codes.DeadlineExceeded {
+		t.Errorf("Bad watch error: %v", err)
+	}
+
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+	if len(s.findEntry(queued.GetId(), 0).watchers) != 0 {
+		t.Errorf("Watcher was left behind")
+	}
+}
+
+func TestCopyEventProgress(t *testing.T) {
+	s := InitTestServer()
+	queued, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+
+	s.queueMutex.Lock()
+	defer s.queueMutex.Unlock()
+	entry := s.findEntry(queued.GetId(), 0)
+	entry.resp.Status = pb.CopyStatus_IN_PROGRESS
+	entry.timeStarted = time.Now().Add(-time.Second * 2)
+	s.trackTransfer(entry.req, &fixedTransfer{done: 100, total: 300})
+
+	e := s.copyEvent(entry)
+	if e.GetBytesDone() != 100 || e.GetBytesTotal() != 300 {
+		t.Errorf("Bad progress: %v", e)
+	}
+	if e.GetBytesPerSecond() < 45 || e.GetBytesPerSecond() > 50 {
+		t.Errorf("Bad rate: %v", e.GetBytesPerSecond())
+	}
+	if e.GetEtaMillis() < 3900 || e.GetEtaMillis() > 4500 {
+		t.Errorf("Bad eta: %v", e.GetEtaMillis())
+	}
+
+	s.untrackTransfer(entry.req)
+	if e := s.copyEvent(entry); e.GetBytesDone() != 0 || e.GetEtaMillis() != 0 {
+		t.Errorf("Progress outlived the transfer: %v", e)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"github.com/brotherlogic/goserver/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// limits caps how many copies run at once, overall and against any one server
+type limits struct {
+	global    int
+	perInput  int
+	perOutput int
+}
+
+func (s *Server) setError(err string) {
+	s.ccopiesMutex.Lock()
+	defer s.ccopiesMutex.Unlock()
+	s.lastError = err
+}
+
+func (s *Server) serverName(server string) string {
+	if s.isLocal(server) {
+		return s.Registry.GetIdentifier()
+	}
+	return server
+}
+
+// writeKey names the file a copy writes to
+func (s *Server) writeKey(in *pb.CopyRequest) string {
+	return s.serverName(in.GetOutputServer()) + ":" + in.GetOutputFile()
+}
+
+// acquire takes a slot to run the copy, returning false if that would take us over a limit
+// or something else is already writing the same file
+func (s *Server) acquire(in *pb.CopyRequest) bool {
+	input, output := s.serverName(in.GetInputServer()), s.serverName(in.GetOutputServer())
+
+	s.ccopiesMutex.Lock()
+	defer s.ccopiesMutex.Unlock()
+
+	if s.ccopies >= int64(s.limits.global) || s.inputCopies[input] >= s.limits.perInput || s.outputCopies[output] >= s.limits.perOutput || s.writing[s.writeKey(in)] {
+		return false
+	}
+
+	s.ccopies++
+	s.inputCopies[input]++
+	s.outputCopies[output]++
+	s.writing[s.writeKey(in)] = true
+	return true
+}
+
+// release gives back the slot taken by acquire
+func (s *Server) release(in *pb.CopyRequest) {
+	input, output := s.serverName(in.GetInputServer()), s.serverName(in.GetOutputServer())
+
+	s.ccopiesMutex.Lock()
+	s.ccopies--
+	s.inputCopies[input]--
+	if s.inputCopies[input] <= 0 {
+		delete(s.inputCopies, input)
+	}
+	s.outputCopies[output]--
+	if s.outputCopies[output] <= 0 {
+		delete(s.outputCopies, output)
+	}
+	delete(s.writing, s.writeKey(in))
+	s.ccopiesMutex.Unlock()
+
+	// Something waiting on this slot may now be able to run
+	s.scheduler.wake()
+}
+
+func (s *Server) tooBusy(in *pb.CopyRequest) error {
+	s.ccopiesMutex.Lock()
+	defer s.ccopiesMutex.Unlock()
+	if s.writing[s.writeKey(in)] {
+		return status.Errorf(codes.AlreadyExists, "%v is already being copied to", in.GetOutputFile())
+	}
+	return status.Errorf(codes.ResourceExhausted, "Too many concurrent copies from %v (%+v)", s.Registry.GetIdentifier(), s.current)
+}
+
+// runQueue hands out queued copies to run as slots come free
+func (s *Server) runQueue() {
+	for {
+		entry := s.scheduler.pop(func(e *queueEntry) bool { return s.acquire(e.req) })
+		go s.runEntry(entry)
+	}
+}
+
+func (s *Server) runEntry(entry *queueEntry) {
+	defer s.release(entry.req)
+
+	ctx, cancel := utils.ManualContext(fmt.Sprintf("copy-for-%v", entry.req.InputFile), time.Hour)
+	defer cancel()
+
+	s.queueMutex.Lock()
+	if entry.cancelled {
+		// Cancelled between being picked and starting
+		entry.resp.Status = pb.CopyStatus_CANCELLED
+		entry.timeFinished = time.Now()
+		s.persist(ctx, entry)
+		cb := callbackFor(entry)
+		s.queueMutex.Unlock()
+		s.sendCallback(ctx, entry.req, cb)
+		return
+	}
+	entry.resp.Status = pb.CopyStatus_IN_PROGRESS
+	entry.timeStarted = time.Now()
+	entry.cancel = cancel
+	s.persist(ctx, entry)
+	s.queueMutex.Unlock()
+
+	// Status reads the entry under the lock, so the copy reports into its own response
+	result := &pb.CopyResponse{}
+	err := s.runCopy(ctx, entry.req, result)
+
+	s.queueMutex.Lock()
+	entry.cancel = nil
+	s.clearProgress(entry)
+	entry.resp.BytesTransferred = result.GetBytesTransferred()
+	entry.resp.Checksum = result.GetChecksum()
+	entry.resp.Metadata = result.GetMetadata()
+	entry.resp.FilesSkipped = result.GetFilesSkipped()
+	entry.resp.BytesSaved = result.GetBytesSaved()
+	retry := false
+	if entry.cancelled && err != nil {
+		entry.resp.Status = pb.CopyStatus_CANCELLED
+		entry.resp.Error = fmt.Sprintf("%v", err)
+		entry.resp.ErrorCode = int32(codes.Canceled)
+		entry.timeFinished = time.Now()
+	} else if policy := retryPolicy(entry.req); retryable(policy, err) {
+		s.CtxLog(ctx, fmt.Sprintf("CopyFailed %v", entry))
+		entry.resp.Status = pb.CopyStatus_IN_QUEUE
+		entry.resp.Repeats++
+		entry.resp.Error = fmt.Sprintf("%v", err)
+		entry.resp.ErrorCode = int32(status.Convert(err).Code())
+		retry = entry.resp.Repeats < policy.GetMaxAttempts()
+		if retry {
+			entry.resp.NextAttemptTime = time.Now().Add(backoff(policy, entry.resp.Repeats)).UnixNano()
+		} else {
+			s.deadLetter(ctx, entry)
+		}
+	} else {
+		entry.resp.Error = ""
+		entry.resp.ErrorCode = 0
+		entry.resp.NextAttemptTime = 0
+		if err != nil {
+			entry.resp.Error = fmt.Sprintf("%v", err)
+			entry.resp.ErrorCode = int32(status.Convert(err).Code())
+		}
+		entry.resp.Status = finalStatus(err, result)
+		entry.timeFinished = time.Now()
+		entry.resp.MillisToCopy = entry.timeFinished.Sub(entry.timeStarted).Milliseconds()
+	}
+	s.persist(ctx, entry)
+	cb := callbackFor(entry)
+	s.queueMutex.Unlock()
+
+	if retry {
+		retries.Inc()
+		s.scheduler.push(entry, time.Now())
+	} else {
+		s.sendCallback(ctx, entry.req, cb)
+		s.jobs.wake()
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "github.com/brotherlogic/filecopier/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAcquireLimits(t *testing.T) {
+	s := InitTestServer()
+	s.limits = limits{global: 3, perInput: 2, perOutput: 1}
+
+	first := &pb.CopyRequest{InputServer: "in", OutputServer: "out1"}
+	if !s.acquire(first) {
+		t.Fatalf("Unable to acquire first slot")
+	}
+	if s.acquire(&pb.CopyRequest{InputServer: "other", OutputServer: "out1"}) {
+		t.Errorf("Output limit was ignored")
+	}
+	if !s.acquire(&pb.CopyRequest{InputServer: "in", OutputServer: "out2"}) {
+		t.Fatalf("Unable to acquire second slot")
+	}
+	if s.acquire(&pb.CopyRequest{InputServer: "in", OutputServer: "out3"}) {
+		t.Errorf("Input limit was ignored")
+	}
+	if !s.acquire(&pb.CopyRequest{InputServer: "other", OutputServer: "out3"}) {
+		t.Fatalf("Unable to acquire third slot")
+	}
+	if s.acquire(&pb.CopyRequest{InputServer: "another", OutputServer: "out4"}) {
+		t.Errorf("Global limit was ignored")
+	}
+
+	s.release(first)
+	if !s.acquire(&pb.CopyRequest{InputServer: "another", OutputServer: "out1"}) {
+		t.Errorf("Released slot was not freed")
+	}
+}
+
+func TestAcquireSameFile(t *testing.T) {
+	s := InitTestServer()
+	s.limits = limits{global: 4, perInput: 4, perOutput: 4}
+
+	first := &pb.CopyRequest{InputFile: "a", OutputServer: "out", OutputFile: "same"}
+	if !s.acquire(first) {
+		t.Fatalf("Unable to acquire first slot")
+	}
+	if s.acquire(&pb.CopyRequest{InputFile: "b", OutputServer: "out", OutputFile: "same"}) {
+		t.Errorf("Two copies were writing the same file")
+	}
+	if !s.acquire(&pb.CopyRequest{InputFile: "b", OutputServer: "other", OutputFile: "same"}) {
+		t.Errorf("Same file on another server was blocked")
+	}
+
+	s.release(first)
+	if !s.acquire(&pb.CopyRequest{InputFile: "b", OutputServer: "out", OutputFile: "same"}) {
+		t.Errorf("Released file was not freed")
+	}
+}
+
+func TestQueueKeepsRunningCopy(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &blockingTransport{}
+	go s.runQueue()
+
+	first, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt"})
+	waitForStatus(t, s, first.GetId(), pb.CopyStatus_IN_PROGRESS)
+
+	again, err := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: dir + "/out.txt", Override: true})
+	if err != nil || again.GetId() != first.GetId() || again.GetStatus() != pb.CopyStatus_IN_PROGRESS {
+		t.Errorf("Running copy was replaced: %v, %v", again, err)
+	}
+
+	time.Sleep(time.Millisecond * 50)
+	s.ccopiesMutex.Lock()
+	running := s.ccopies
+	s.ccopiesMutex.Unlock()
+	if running != 1 {
+		t.Errorf("Copy was run twice: %v running", running)
+	}
+
+	if _, err := s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: first.GetId()}); err != nil {
+		t.Errorf("Running copy was lost: %v", err)
+	}
+	waitForStatus(t, s, first.GetId(), pb.CopyStatus_CANCELLED)
+}
+
+func TestCopyOfAFileBeingWritten(t *testing.T) {
+	s := InitTestServer()
+	s.acquire(&pb.CopyRequest{InputFile: "other", OutputFile: "out"})
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputFile: "out"})
+	if status.Convert(err).Code() != codes.AlreadyExists {
+		t.Errorf("Copy ran over one in progress: %v", err)
+	}
+}
+
+func TestSchedulerSkipsBlocked(t *testing.T) {
+	s := newScheduler()
+	now := time.Now()
+	blocked := entryWithPriority("blocked", 0)
+	free := entryWithPriority("free", 10)
+	s.push(blocked, now)
+	s.push(free, now)
+
+	entry := s.pop(func(e *queueEntry) bool { return e != blocked })
+	if entry != free {
+		t.Errorf("Blocked entry held up the queue: %v", entry.req)
+	}
+	if s.pop(nil) != blocked {
+		t.Errorf("Blocked entry was lost")
+	}
+}
+
+func TestCopyRespectsLimits(t *testing.T) {
+	s := InitTestServer()
+	s.limits = limits{global: 4, perInput: 4, perOutput: 1}
+	s.acquire(&pb.CopyRequest{OutputServer: "busy"})
+
+	_, err := s.Copy(context.Background(), &pb.CopyRequest{InputFile: "in", OutputServer: "busy", OutputFile: "out"})
+	if status.Convert(err).Code() != codes.ResourceExhausted {
+		t.Errorf("Copy ran on a busy server: %v", err)
+	}
+}
+
+func TestQueueRunsInParallel(t *testing.T) {
+	s, dir := InitStreamTestServer(t)
+	s.limits = limits{global: 2, perInput: 2, perOutput: 2}
+	s.transports[pb.TransportType_DEFAULT_TRANSPORT] = &blockingTransport{}
+	go s.runQueue()
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		resp, _ := s.QueueCopy(context.Background(), &pb.CopyRequest{InputFile: dir + "/in.txt", OutputFile: fmt.Sprintf("%v/out%v.txt", dir, i), Priority: int32(i)})
+		ids = append(ids, resp.GetId())
+	}
+
+	waitForStatus(t, s, ids[0], pb.CopyStatus_IN_PROGRESS)
+	waitForStatus(t, s, ids[1], pb.CopyStatus_IN_PROGRESS)
+	time.Sleep(time.Millisecond * 50)
+	resp, _ := s.GetCopyStatus(context.Background(), &pb.CopyStatusRequest{Id: ids[2]})
+	if resp.GetEntry().GetResp().GetStatus() != pb.CopyStatus_IN_QUEUE {
+		t.Errorf("Limit was ignored: %v", resp)
+	}
+
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: ids[0]})
+	waitForStatus(t, s, ids[2], pb.CopyStatus_IN_PROGRESS)
+
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: ids[1]})
+	s.CancelCopy(context.Background(), &pb.CancelCopyRequest{Id: ids[2]})
+	waitForStatus(t, s, ids[2], pb.CopyStatus_CANCELLED)
+}